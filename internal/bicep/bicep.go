@@ -0,0 +1,361 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bicep holds the helpers used to write Go and Score values as Bicep literals from the workload and
+// provisioner templates.
+package bicep
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// declarationRegex matches the top level declarations which share the namespace of the symbolic names.
+var declarationRegex = regexp.MustCompile(`(?m)^(?:param|var|resource|module|type|func)\s+([A-Za-z_][A-Za-z0-9_]*)`)
+
+// reservedIdentifiers are the Bicep keywords and literals which can't be used as symbolic names.
+var reservedIdentifiers = []string{
+	"false", "for", "func", "if", "import", "in", "metadata", "module", "null", "output", "param", "resource",
+	"targetScope", "true", "type", "var",
+}
+
+// FuncMap returns the template functions writing Bicep literals. The value functions accept an optional indentation,
+// the number of spaces before the line the value starts on, e.g. {{ bicepValue .Params.tags 6 }}.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"bicepString":          func(value interface{}) string { return String(toString(value)) },
+		"bicepLiteralString":   func(value interface{}) string { return LiteralString(toString(value)) },
+		"bicepMultilineString": func(value interface{}) string { return MultilineString(toString(value)) },
+		"bicepIdentifier":      func(value interface{}) string { return Identifier(toString(value)) },
+		"bicepKey":             func(value interface{}) string { return Key(toString(value)) },
+		"bicepValue": func(value interface{}, indent ...int) (string, error) {
+			return Value(value, firstOrZero(indent))
+		},
+		"bicepInterpolatedValue": func(value interface{}, indent ...int) (string, error) {
+			return InterpolatedValue(value, firstOrZero(indent))
+		},
+		"bicepArray": func(value interface{}, indent ...int) (string, error) {
+			if k := indirect(reflect.ValueOf(value)).Kind(); k != reflect.Slice && k != reflect.Array {
+				return "", fmt.Errorf("bicepArray: expected a list, got %T", value)
+			}
+			return Value(value, firstOrZero(indent))
+		},
+		"bicepObject": func(value interface{}, indent ...int) (string, error) {
+			if k := indirect(reflect.ValueOf(value)).Kind(); k != reflect.Map {
+				return "", fmt.Errorf("bicepObject: expected a map, got %T", value)
+			}
+			return Value(value, firstOrZero(indent))
+		},
+	}
+}
+
+// StringPart is a part of a Bicep string built with JoinString.
+type StringPart struct {
+	Text string
+	// Interpolate indicates that the ${...} expressions of the Text are kept as Bicep interpolations rather than
+	// escaped.
+	Interpolate bool
+}
+
+// JoinString returns the parts as a single single-quoted Bicep string. This is used when only some parts of a string
+// are resolved resource outputs which may reference the properties of other Bicep resources.
+func JoinString(parts ...StringPart) string {
+	sb := new(strings.Builder)
+	sb.WriteRune('\'')
+	for _, part := range parts {
+		writeStringContent(sb, part.Text, part.Interpolate)
+	}
+	sb.WriteRune('\'')
+	return sb.String()
+}
+
+// String returns the value as a single-quoted Bicep string. Any ${...} interpolation is kept as is since the
+// resource outputs use them to reference the properties of other Bicep resources.
+func String(value string) string {
+	return JoinString(StringPart{Text: value, Interpolate: true})
+}
+
+// LiteralString returns the value as a single-quoted Bicep string without any interpolation.
+func LiteralString(value string) string {
+	return JoinString(StringPart{Text: value})
+}
+
+// MultilineString returns the value as a Bicep multi-line string, delimited by three single quotes, which is written
+// as is and without any interpolation. Values which contain three single quotes or end with one can't be written this
+// way and fall back to LiteralString.
+func MultilineString(value string) string {
+	if strings.Contains(value, "'''") || strings.HasSuffix(value, "'") {
+		return LiteralString(value)
+	}
+	return "'''\n" + value + "'''"
+}
+
+// Identifier returns the value sanitised as a Bicep symbolic name: any character which is not a letter, a digit, or
+// an underscore is replaced by an underscore, and a leading digit or a reserved word gets an extra underscore.
+func Identifier(value string) string {
+	sb := new(strings.Builder)
+	for _, c := range value {
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			sb.WriteRune(c)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	out := sb.String()
+	if out == "" || (out[0] >= '0' && out[0] <= '9') {
+		out = "_" + out
+	}
+	if slices.Contains(reservedIdentifiers, out) {
+		out += "_"
+	}
+	return out
+}
+
+// DeclaredNames returns the symbolic names declared at the top level of the Bicep declarations, in order.
+func DeclaredNames(declarations string) []string {
+	out := make([]string, 0)
+	for _, match := range declarationRegex.FindAllStringSubmatch(declarations, -1) {
+		out = append(out, match[1])
+	}
+	return out
+}
+
+// Key returns the value as a Bicep object property name, quoted when it is not a valid identifier.
+func Key(value string) string {
+	if identifierRegex.MatchString(value) {
+		return value
+	}
+	return LiteralString(value)
+}
+
+// Value returns a Go value as a Bicep literal. Strings are written with LiteralString so that any value, like the
+// params of a Score resource, is written as is. Maps and structs decoded from yaml or json are written as objects with
+// sorted properties, and slices as arrays. The nested lines are indented relative to the indent spaces of the first
+// line.
+func Value(value interface{}, indent int) (string, error) {
+	sb := new(strings.Builder)
+	if err := writeValue(sb, reflect.ValueOf(value), indent, false); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// InterpolatedValue is like Value, but the strings are written with String so that the ${...} expressions of the
+// resource outputs are kept as Bicep interpolations.
+func InterpolatedValue(value interface{}, indent int) (string, error) {
+	sb := new(strings.Builder)
+	if err := writeValue(sb, reflect.ValueOf(value), indent, true); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func writeValue(sb *strings.Builder, v reflect.Value, indent int, interpolate bool) error {
+	v = indirect(v)
+	if !v.IsValid() {
+		sb.WriteString("null")
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		sb.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		sb.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			sb.WriteString("json(" + LiteralString(strconv.FormatUint(v.Uint(), 10)) + ")")
+		} else {
+			sb.WriteString(strconv.FormatUint(v.Uint(), 10))
+		}
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("%v can't be written as a Bicep number", f)
+		} else if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			sb.WriteString(strconv.FormatInt(int64(f), 10))
+		} else {
+			// Bicep has no floating point literals, but they can be parsed from json
+			sb.WriteString("json(" + LiteralString(strconv.FormatFloat(f, 'g', -1, 64)) + ")")
+		}
+	case reflect.String:
+		sb.WriteString(JoinString(StringPart{Text: v.String(), Interpolate: interpolate}))
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			sb.WriteString("[]")
+			return nil
+		}
+		sb.WriteString("[")
+		for i := 0; i < v.Len(); i++ {
+			sb.WriteString("\n" + strings.Repeat(" ", indent+2))
+			if err := writeValue(sb, v.Index(i), indent+2, interpolate); err != nil {
+				return fmt.Errorf("%d: %w", i, err)
+			}
+		}
+		sb.WriteString("\n" + strings.Repeat(" ", indent) + "]")
+	case reflect.Map:
+		keys := make(map[string]reflect.Value, v.Len())
+		for _, k := range v.MapKeys() {
+			keys[fmt.Sprint(indirect(k).Interface())] = v.MapIndex(k)
+		}
+		return writeObject(sb, keys, indent, interpolate)
+	case reflect.Struct:
+		return writeValue(sb, reflect.ValueOf(structToMap(v)), indent, interpolate)
+	default:
+		return fmt.Errorf("%s can't be written as a Bicep value", v.Type())
+	}
+	return nil
+}
+
+func writeObject(sb *strings.Builder, properties map[string]reflect.Value, indent int, interpolate bool) error {
+	if len(properties) == 0 {
+		sb.WriteString("{}")
+		return nil
+	}
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	sb.WriteString("{")
+	for _, name := range names {
+		sb.WriteString("\n" + strings.Repeat(" ", indent+2) + Key(name) + ": ")
+		if err := writeValue(sb, properties[name], indent+2, interpolate); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	sb.WriteString("\n" + strings.Repeat(" ", indent) + "}")
+	return nil
+}
+
+// structToMap returns the exported fields of a struct by their yaml name, omitting the nil fields, so that the Score
+// types are written like their yaml representation.
+func structToMap(v reflect.Value) map[string]interface{} {
+	out := make(map[string]interface{}, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		} else if name == "" {
+			name = field.Name
+		}
+		fv := v.Field(i)
+		if isNil(fv) || (strings.Contains(opts, "omitempty") && fv.IsZero()) {
+			continue
+		}
+		out[name] = fv.Interface()
+	}
+	return out
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
+
+// indirect follows pointers and interfaces to the underlying value, it returns an invalid value for nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// toString returns the string of a template argument, following pointers like the Score optional fields.
+func toString(value interface{}) string {
+	v := indirect(reflect.ValueOf(value))
+	if !v.IsValid() {
+		return ""
+	} else if v.Kind() == reflect.String {
+		return v.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func firstOrZero(values []int) int {
+	if len(values) > 0 {
+		return values[0]
+	}
+	return 0
+}
+
+func writeStringContent(sb *strings.Builder, value string, interpolate bool) {
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '$' && i+1 < len(value) && value[i+1] == '{':
+			if end := interpolationEnd(value, i+2); interpolate && end > i+2 {
+				sb.WriteString(value[i : end+1])
+				i = end
+			} else {
+				sb.WriteString(`\$`)
+			}
+		case c == '\\':
+			sb.WriteString(`\\`)
+		case c == '\'':
+			sb.WriteString(`\'`)
+		case c == '\n':
+			sb.WriteString(`\n`)
+		case c == '\r':
+			sb.WriteString(`\r`)
+		case c == '\t':
+			sb.WriteString(`\t`)
+		case c < 0x20 || c == 0x7f:
+			sb.WriteString(fmt.Sprintf(`\u{%X}`, c))
+		default:
+			sb.WriteByte(c)
+		}
+	}
+}
+
+// interpolationEnd returns the index of the '}' closing the interpolation expression starting at start, or -1 if the
+// expression is not closed. Braces and quotes within the expression are taken into account.
+func interpolationEnd(value string, start int) int {
+	depth := 0
+	inString := false
+	for i := start; i < len(value); i++ {
+		switch c := value[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '\'':
+			inString = !inString
+		case inString:
+		case c == '{':
+			depth++
+		case c == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
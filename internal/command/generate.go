@@ -16,9 +16,12 @@ package command
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 
@@ -30,6 +33,7 @@ import (
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
+	"github.com/score-spec/score-radius/internal/bicep"
 	"github.com/score-spec/score-radius/internal/convert"
 	"github.com/score-spec/score-radius/internal/provisioners"
 	"github.com/score-spec/score-radius/internal/provisioners/loader"
@@ -37,10 +41,13 @@ import (
 )
 
 const (
-	generateCmdOverridesFileFlag    = "overrides-file"
-	generateCmdOverridePropertyFlag = "override-property"
-	generateCmdImageFlag            = "image"
-	generateCmdOutputFlag           = "output"
+	generateCmdOverridesFileFlag     = "overrides-file"
+	generateCmdOverridePropertyFlag  = "override-property"
+	generateCmdImageFlag             = "image"
+	generateCmdOutputFlag            = "output"
+	generateCmdFrozenFlag            = "frozen"
+	generateCmdExtenderFallbackFlag  = "extender-fallback"
+	generateCmdEnvironmentValuesFlag = "environment-values"
 )
 
 var generateCmd = &cobra.Command{
@@ -121,7 +128,16 @@ var generateCmd = &cobra.Command{
 				}
 			}
 
-			if currentState, err = currentState.WithWorkload(&workload, &arg, state.WorkloadExtras{}); err != nil {
+			if err := convert.ValidateWorkload(&workload); err != nil {
+				return fmt.Errorf("invalid score file: %s: %w", arg, err)
+			}
+
+			// keep the extras of a workload generated before, like its symbolic name
+			var extras state.WorkloadExtras
+			if workloadName, _ := workload.Metadata["name"].(string); workloadName != "" {
+				extras = currentState.Workloads[workloadName].Extras
+			}
+			if currentState, err = currentState.WithWorkload(&workload, &arg, extras); err != nil {
 				return fmt.Errorf("failed to add score file to project: %s: %w", arg, err)
 			}
 			slog.Info("Added score file to project", "file", arg)
@@ -136,15 +152,38 @@ var generateCmd = &cobra.Command{
 		}
 
 		slog.Info("Primed resources", "#workloads", len(currentState.Workloads), "#resources", len(currentState.Resources))
+		currentState = state.WithSymbolicNames(currentState)
+
+		lock, err := loader.LoadLock(sd.Path)
+		if err != nil {
+			return err
+		}
+		if v, _ := cmd.Flags().GetBool(generateCmdFrozenFlag); v {
+			if _, err := os.Stat(filepath.Join(sd.Path, loader.LockFileName)); err != nil {
+				return fmt.Errorf("--%s requires a provisioners lock: %w", generateCmdFrozenFlag, err)
+			} else if err := lock.Verify(sd.Path); err != nil {
+				return fmt.Errorf("provisioners don't match the lock: %w", err)
+			}
+		} else if _, err := os.Stat(filepath.Join(sd.Path, loader.LockFileName)); err == nil {
+			if err := lock.Verify(sd.Path); err != nil {
+				slog.Warn(fmt.Sprintf("Provisioners don't match the lock, run 'score-radius provisioners update' to lock them: %v", err))
+			}
+		}
 
 		localProvisioners, err := loader.LoadProvisionersFromDirectory(sd.Path, loader.ProvisionersFileSuffix)
 		if err != nil {
 			return fmt.Errorf("failed to load provisioners")
 		}
 		slog.Info("Loaded provisioners", "#provisioners", len(localProvisioners))
+		// the environment resources are read from the params of the generated file, unless a provisioner file overrides it
+		environmentParams := provisioners.NewEnvironmentParams()
+		localProvisioners = append(localProvisioners, environmentParams.Provisioner())
+		if v, _ := cmd.Flags().GetBool(generateCmdExtenderFallbackFlag); v {
+			localProvisioners = append(localProvisioners, provisioners.ExtenderFallback)
+		}
 
 		var resourcesManifests string
-		if resourcesManifests, currentState, err = provisioners.ProvisionResources(currentState, localProvisioners); err != nil {
+		if resourcesManifests, currentState, err = provisioners.ProvisionResources(cmd.Context(), currentState, localProvisioners); err != nil {
 			return fmt.Errorf("failed to provision resources: %w", err)
 		}
 
@@ -154,18 +193,17 @@ var generateCmd = &cobra.Command{
 		}
 		slog.Info("Persisted state file")
 
-		out := new(bytes.Buffer)
-		for workloadName := range currentState.Workloads {
-			if manifest, err := convert.Workload(currentState, workloadName); err != nil {
-				return fmt.Errorf("failed to convert workloads: %w", err)
-			} else {
-				out.WriteString(manifest)
+		if valuesPath, _ := cmd.Flags().GetString(generateCmdEnvironmentValuesFlag); valuesPath != "" {
+			var values map[string]interface{}
+			if err := decodeYamlFile(valuesPath, &values); err != nil {
+				return fmt.Errorf("--%s is invalid: %w", generateCmdEnvironmentValuesFlag, err)
 			}
-			slog.Info(fmt.Sprintf("Wrote manifest to manifests buffer for workload '%s'", workloadName))
+			environmentParams.SetValues(values)
+		}
+		out, err := composeBicepFile(currentState, resourcesManifests, environmentParams)
+		if err != nil {
+			return err
 		}
-
-		out.WriteString(resourcesManifests)
-		slog.Info("Wrote resources manifests to manifests buffer")
 
 		v, _ := cmd.Flags().GetString(generateCmdOutputFlag)
 		if v == "" {
@@ -179,10 +217,85 @@ var generateCmd = &cobra.Command{
 		} else {
 			slog.Info(fmt.Sprintf("Wrote manifests to '%s'", v))
 		}
+
+		paramsPath := strings.TrimSuffix(v, filepath.Ext(v)) + ".bicepparam"
+		if len(environmentParams.Names()) > 0 && v == "-" {
+			slog.Warn("The environment params are not written to a .bicepparam file when the output is the standard output")
+		} else if len(environmentParams.Names()) > 0 {
+			content, err := composeBicepParamFile(filepath.Base(v), environmentParams)
+			if err != nil {
+				return err
+			} else if err := os.WriteFile(paramsPath, []byte(content), 0644); err != nil {
+				return fmt.Errorf("failed to write params file: %w", err)
+			}
+			slog.Info(fmt.Sprintf("Wrote the environment params to '%s'", paramsPath))
+		} else if v != "-" {
+			// a params file left from a previous generation would not match the params of the new Bicep file
+			if err := os.Remove(paramsPath); err == nil {
+				slog.Info(fmt.Sprintf("Removed '%s' since the Bicep file has no environment params", paramsPath))
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to remove params file: %w", err)
+			}
+		}
 		return nil
 	},
 }
 
+// composeBicepFile writes the single Bicep file made of the shared header and the environment params, followed by each
+// workload in name order, followed by the resources manifests in their provisioning order. The same state always
+// results in the same content.
+func composeBicepFile(currentState *state.State, resourcesManifests string, environmentParams *provisioners.EnvironmentParams) (*bytes.Buffer, error) {
+	workloads := new(bytes.Buffer)
+	for _, workloadName := range slices.Sorted(maps.Keys(currentState.Workloads)) {
+		if manifest, err := convert.Workload(currentState, workloadName); err != nil {
+			return nil, fmt.Errorf("failed to convert workloads: %w", err)
+		} else {
+			workloads.WriteString(manifest)
+		}
+		slog.Info(fmt.Sprintf("Wrote manifest to manifests buffer for workload '%s'", workloadName))
+	}
+
+	// the environment params are known once the workloads have looked up their outputs
+	out := new(bytes.Buffer)
+	out.WriteString(convert.Header())
+	out.WriteString(environmentParams.Declarations())
+	out.Write(workloads.Bytes())
+	out.WriteString(resourcesManifests)
+	slog.Info("Wrote resources manifests to manifests buffer")
+	return out, nil
+}
+
+// composeBicepParamFile writes the .bicepparam file of the environment params of the Bicep file. Each param is set from
+// its value, or else from the environment variable of the same name. The secure params are always read from the
+// environment variable when deploying so that secrets are never written to the file, their values are ignored.
+func composeBicepParamFile(bicepFileName string, environmentParams *provisioners.EnvironmentParams) (string, error) {
+	out := new(strings.Builder)
+	_, _ = fmt.Fprintf(out, "using %s\n\n", bicep.LiteralString(bicepFileName))
+	for _, name := range environmentParams.Names() {
+		value, hasValue := environmentParams.Value(name)
+		if environmentParams.Secure(name) {
+			if hasValue {
+				slog.Warn(fmt.Sprintf("The value of the secure param '%s' is ignored, it is read from the environment variable when deploying", name))
+			}
+			_, _ = fmt.Fprintf(out, "param %s = readEnvironmentVariable(%s)\n", name, bicep.LiteralString(name))
+		} else if hasValue {
+			if environmentParams.Type(name) == "string" {
+				value = fmt.Sprint(value)
+			}
+			raw, err := bicep.Value(value, 0)
+			if err != nil {
+				return "", fmt.Errorf("--%s: %s: %w", generateCmdEnvironmentValuesFlag, name, err)
+			}
+			_, _ = fmt.Fprintf(out, "param %s = %s\n", name, raw)
+		} else if value, ok := os.LookupEnv(name); ok {
+			_, _ = fmt.Fprintf(out, "param %s = %s\n", name, bicep.LiteralString(value))
+		} else {
+			_, _ = fmt.Fprintf(out, "param %s = readEnvironmentVariable(%s)\n", name, bicep.LiteralString(name))
+		}
+	}
+	return out.String(), nil
+}
+
 func parseAndApplyOverrideFile(entry string, flagName string, spec map[string]interface{}) error {
 	if raw, err := os.ReadFile(entry); err != nil {
 		return fmt.Errorf("--%s '%s' is invalid, failed to read file: %w", flagName, entry, err)
@@ -229,5 +342,8 @@ func init() {
 	generateCmd.Flags().String(generateCmdOverridesFileFlag, "", "An optional file of Score overrides to merge in")
 	generateCmd.Flags().StringArray(generateCmdOverridePropertyFlag, []string{}, "An optional set of path=key overrides to set or remove")
 	generateCmd.Flags().StringP(generateCmdImageFlag, "i", "", "An optional container image to use for any container with image == '.'")
+	generateCmd.Flags().Bool(generateCmdFrozenFlag, false, "Fail if the provisioners files don't match the provisioners lock, instead of updating the lock")
+	generateCmd.Flags().Bool(generateCmdExtenderFallbackFlag, false, "Provision the resources without provisioner as Applications.Core/extenders instead of failing")
+	generateCmd.Flags().String(generateCmdEnvironmentValuesFlag, "", "An optional yaml file of the values of the environment params written to the .bicepparam file")
 	rootCmd.AddCommand(generateCmd)
 }
@@ -16,13 +16,16 @@ package command
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/score-spec/score-radius/internal/provisioners/loader"
 	"github.com/score-spec/score-radius/internal/state"
 )
 
@@ -230,8 +233,2431 @@ resource example 'Applications.Core/containers@2023-10-01-preview' = {
         disableDefaultEnvVars: false
       }
     }
+    runtimes: {
+      kubernetes: {
+        base: string({
+          apiVersion: 'v1'
+          kind: 'ConfigMap'
+          metadata: {
+            name: 'example-files'
+          }
+          data: {
+            'main-0': 'example\n'
+          }
+        })
+        pod: {
+          containers: [
+            {
+              name: 'example'
+              volumeMounts: [
+                {
+                  name: 'score-files'
+                  mountPath: '/somefile'
+                  subPath: 'main-0'
+                  readOnly: true
+                }
+              ]
+            }
+          ]
+          volumes: [
+            {
+              name: 'score-files'
+              configMap: {
+                name: 'example-files'
+                items: [
+                  {
+                    key: 'main-0'
+                    path: 'main-0'
+                  }
+                ]
+              }
+            }
+          ]
+        }
+      }
+    }
+  }
+}
+`, string(raw))
+}
+
+func TestInitAndGenerate_with_multiple_containers(t *testing.T) {
+	td := changeToTempDir(t)
+	stdout, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+	assert.Equal(t, "", stdout)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  proxy:
+    image: envoyproxy/envoy
+    args:
+      - "--config-path"
+      - /etc/envoy/envoy.yaml
+    readinessProbe:
+      httpGet:
+        port: 9901
+        path: /ready
+  app:
+    image: stefanprodan/podinfo
+    variables:
+      key: value
+  logs:
+    image: fluent/fluent-bit
+    variables:
+      LOG_LEVEL: info
+`), 0755))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"generate", "-o", "app.bicep", "--", "score.yaml",
+	})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	assert.NoError(t, err)
+	assert.Equal(t, `
+extension radius
+
+@description('The Radius Application ID. Injected automatically by the rad CLI.')
+param application string
+
+@description('The Radius Environment ID. Injected automatically by the rad CLI.')
+param environment string
+
+// Workload 'example' has multiple containers: 'app' is the main container,
+// 'logs', 'proxy' added as sidecars through the runtimes.kubernetes.pod patch.
+resource example 'Applications.Core/containers@2023-10-01-preview' = {
+  name: 'example'
+  properties: {
+    application: application
+    environment: environment
+    container: {
+      image: 'stefanprodan/podinfo'
+      env: {
+        key: {
+          value: 'value'
+        }
+      }
+    }
+    runtimes: {
+      kubernetes: {
+        pod: {
+          containers: [
+            {
+              name: 'logs'
+              image: 'fluent/fluent-bit'
+              env: [
+                {
+                  name: 'LOG_LEVEL'
+                  value: 'info'
+                }
+              ]
+            }
+            {
+              name: 'proxy'
+              image: 'envoyproxy/envoy'
+              args: [
+                '--config-path'
+                '/etc/envoy/envoy.yaml'
+              ]
+              readinessProbe: {
+                httpGet: {
+                  port: 9901
+                  path: '/ready'
+                }
+              }
+            }
+          ]
+        }
+      }
+    }
+  }
+}
+`, string(raw))
+}
+
+func TestInitAndGenerate_with_main_container_annotation(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+  annotations:
+    radius.score.dev/main-container: web
+containers:
+  web:
+    image: nginx
+  agent:
+    image: busybox
+`), 0755))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"generate", "-o", "app.bicep", "--", "score.yaml",
+	})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(raw), `// Workload 'example' has multiple containers: 'web' is the main container,
+// 'agent' added as sidecars through the runtimes.kubernetes.pod patch.`)
+	assert.Contains(t, string(raw), `      image: 'nginx'`)
+	assert.Equal(t, 1, strings.Count(string(raw), "resource example "))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+  annotations:
+    radius.score.dev/main-container: unknown
+containers:
+  web:
+    image: nginx
+`), 0755))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"generate", "-o", "app.bicep", "--", "score.yaml",
+	})
+	assert.EqualError(t, err, "failed to convert workloads: workload: example: annotation 'radius.score.dev/main-container': container 'unknown' does not exist")
+}
+
+func TestInitAndGenerate_with_multiple_workloads(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score-b.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: backend
+containers:
+  main:
+    image: busybox
+resources:
+  db:
+    type: thing
+    id: shared
+`), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score-a.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: frontend
+containers:
+  main:
+    image: nginx
+resources:
+  db:
+    type: thing
+    id: shared
+  cache:
+    type: thing
+`), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, ".score-radius", "thing.provisioners.yaml"), []byte(`
+- uri: template://thing
+  type: thing
+  class: default
+  manifests: |
+    resource {{ splitList "." .Id | last }} 'Applications.Core/extenders@2023-10-01-preview' = {
+      name: '{{ splitList "." .Id | last }}'
+    }
+`), 0644))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"generate", "-o", "app.bicep", "--", "score-b.yaml", "score-a.yaml",
+	})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	assert.NoError(t, err)
+	assert.Equal(t, `
+extension radius
+
+@description('The Radius Application ID. Injected automatically by the rad CLI.')
+param application string
+
+@description('The Radius Environment ID. Injected automatically by the rad CLI.')
+param environment string
+
+resource backend 'Applications.Core/containers@2023-10-01-preview' = {
+  name: 'backend'
+  properties: {
+    application: application
+    environment: environment
+    container: {
+      image: 'busybox'
+    }
+    connections: {
+      db: {
+        source: shared.id
+        disableDefaultEnvVars: false
+      }
+    }
+  }
+}
+
+resource frontend 'Applications.Core/containers@2023-10-01-preview' = {
+  name: 'frontend'
+  properties: {
+    application: application
+    environment: environment
+    container: {
+      image: 'nginx'
+    }
+    connections: {
+      cache: {
+        source: cache.id
+        disableDefaultEnvVars: false
+      }
+      db: {
+        source: shared.id
+        disableDefaultEnvVars: false
+      }
+    }
+  }
+}
+
+resource cache 'Applications.Core/extenders@2023-10-01-preview' = {
+  name: 'cache'
+}
+resource shared 'Applications.Core/extenders@2023-10-01-preview' = {
+  name: 'shared'
+}`, string(raw))
+	assert.Equal(t, 1, strings.Count(string(raw), "extension radius"))
+
+	// generating again from the persisted state must give the same file
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"generate", "-o", "app.bicep",
+	})
+	require.NoError(t, err)
+	raw2, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(raw), string(raw2))
+}
+
+func TestInitAndGenerate_with_files(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "config.txt"), []byte("host=${resources.cache.host}\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  example:
+    image: nginx
+    files:
+      /etc/app/config.txt:
+        source: config.txt
+        mode: "0644"
+      /etc/app/raw.txt:
+        content: "it's ${literal}"
+        noExpand: true
+      /etc/app/secret.txt:
+        content: "password=${resources.cache.password}"
+        mode: "0600"
+  logs:
+    image: busybox
+    files:
+      /etc/logo.png:
+        binaryContent: aGVsbG8=
+resources:
+  cache:
+    type: thing
+`), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, ".score-radius", "thing.provisioners.yaml"), []byte(`
+- uri: template://thing
+  type: thing
+  class: default
+  outputs: |
+    host: ${cache.properties.host}
+    password: ${cache.listSecrets().password}
+`), 0644))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"generate", "-o", "app.bicep", "--", "score.yaml",
+	})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(raw), `
+        base: string({
+          apiVersion: 'v1'
+          kind: 'ConfigMap'
+          metadata: {
+            name: 'example-files'
+          }
+          data: {
+            'example-0': 'host=${cache.properties.host}\n'
+            'example-1': 'it\'s \${literal}'
+          }
+          binaryData: {
+            'logs-0': 'aGVsbG8='
+          }
+        })
+        pod: {
+          containers: [
+            {
+              name: 'example'
+              volumeMounts: [
+                {
+                  name: 'score-files'
+                  mountPath: '/etc/app/config.txt'
+                  subPath: 'example-0'
+                  readOnly: true
+                }
+                {
+                  name: 'score-files'
+                  mountPath: '/etc/app/raw.txt'
+                  subPath: 'example-1'
+                  readOnly: true
+                }
+                {
+                  name: 'score-secret-files'
+                  mountPath: '/etc/app/secret.txt'
+                  subPath: 'example-2'
+                  readOnly: true
+                }
+              ]
+            }
+            {
+              name: 'logs'
+              image: 'busybox'
+              volumeMounts: [
+                {
+                  name: 'score-files'
+                  mountPath: '/etc/logo.png'
+                  subPath: 'logs-0'
+                  readOnly: true
+                }
+              ]
+            }
+          ]
+          volumes: [
+            {
+              name: 'score-files'
+              configMap: {
+                name: 'example-files'
+                items: [
+                  {
+                    key: 'example-0'
+                    path: 'example-0'
+                    mode: 420
+                  }
+                  {
+                    key: 'example-1'
+                    path: 'example-1'
+                  }
+                  {
+                    key: 'logs-0'
+                    path: 'logs-0'
+                  }
+                ]
+              }
+            }
+            {
+              name: 'score-secret-files'
+              secret: {
+                secretName: 'example-secrets'
+                items: [
+                  {
+                    key: 'example-2'
+                    path: 'example-2'
+                    mode: 384
+                  }
+                ]
+              }
+            }
+          ]
+        }
+      }
+    }
+  }
+  dependsOn: [
+    example_secrets
+  ]
+}
+
+resource example_secrets 'Applications.Core/secretStores@2023-10-01-preview' = {
+  name: 'example-secrets'
+  properties: {
+    application: application
+    type: 'generic'
+    data: {
+      'example-2': {
+        value: 'password=${cache.listSecrets().password}'
+      }
+    }
+  }
+}
+`)
+}
+
+func TestInitAndGenerate_with_volumes(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  example:
+    image: nginx
+    volumes:
+      /data:
+        source: ${resources.data}
+      /cache:
+        source: ${resources.tmp}
+      /mnt/secrets:
+        source: ${resources.vault}
+        readOnly: true
+  logs:
+    image: busybox
+    volumes:
+      /var/data:
+        source: ${resources.data}
+        path: logs
+        readOnly: true
+resources:
+  data:
+    type: volume
+  tmp:
+    type: volume
+    class: memory
+  vault:
+    type: volume
+    class: azure-keyvault
+    params:
+      keyVaultId: ${resources.env.KEY_VAULT_ID}
+  env:
+    type: environment
+`), 0755))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"generate", "-o", "app.bicep", "--", "score.yaml",
+	})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	assert.NoError(t, err)
+	assert.Equal(t, `
+extension radius
+
+@description('The Radius Application ID. Injected automatically by the rad CLI.')
+param application string
+
+@description('The Radius Environment ID. Injected automatically by the rad CLI.')
+param environment string
+
+@description('The KEY_VAULT_ID environment value.')
+param KEY_VAULT_ID string
+
+// Workload 'example' has multiple containers: 'example' is the main container,
+// 'logs' added as sidecars through the runtimes.kubernetes.pod patch.
+resource example 'Applications.Core/containers@2023-10-01-preview' = {
+  name: 'example'
+  properties: {
+    application: application
+    environment: environment
+    container: {
+      image: 'nginx'
+      volumes: {
+        'tmp': {
+          kind: 'ephemeral'
+          managedStore: 'memory'
+          mountPath: '/cache'
+        }
+        'vault': {
+          kind: 'persistent'
+          source: '${vault.id}'
+          mountPath: '/mnt/secrets'
+          permission: 'read'
+        }
+      }
+    }
+    runtimes: {
+      kubernetes: {
+        pod: {
+          containers: [
+            {
+              name: 'example'
+              volumeMounts: [
+                {
+                  name: 'data'
+                  mountPath: '/data'
+                }
+              ]
+            }
+            {
+              name: 'logs'
+              image: 'busybox'
+              volumeMounts: [
+                {
+                  name: 'data'
+                  mountPath: '/var/data'
+                  subPath: 'logs'
+                  readOnly: true
+                }
+              ]
+            }
+          ]
+          volumes: [
+            {
+              name: 'data'
+              emptyDir: {}
+            }
+          ]
+        }
+      }
+    }
+  }
+}
+
+resource vault 'Applications.Core/volumes@2023-10-01-preview' = {
+  name: 'vault'
+  properties: {
+    application: application
+    kind: 'azure.com.keyvault'
+    resource: '${KEY_VAULT_ID}'
+  }
+}`, string(raw))
+	raw, err = os.ReadFile(filepath.Join(td, "app.bicepparam"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(raw), "param KEY_VAULT_ID = readEnvironmentVariable('KEY_VAULT_ID')\n")
+}
+
+func TestInitAndGenerate_with_reserved_volume_names(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	for _, name := range []string{"score-files", "score-secret-files"} {
+		t.Run(name, func(t *testing.T) {
+			assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  example:
+    image: nginx
+    volumes:
+      /data:
+        source: ${resources.`+name+`}
+resources:
+  `+name+`:
+    type: volume
+`), 0755))
+			_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "score.yaml"})
+			assert.ErrorContains(t, err, "/data: source: resource '"+name+"' is named like a reserved pod volume, rename it")
+		})
+	}
+}
+
+func TestInitAndGenerate_with_persistent_volume_in_sidecar(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  example:
+    image: nginx
+  logs:
+    image: busybox
+    volumes:
+      /mnt/secrets:
+        source: ${resources.vault}
+resources:
+  vault:
+    type: volume
+    class: azure-keyvault
+    params:
+      keyVaultId: /subscriptions/sub/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/vault
+`), 0755))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"generate", "-o", "app.bicep", "--", "score.yaml",
+	})
+	assert.EqualError(t, err, "failed to convert workloads: workload: example: container: logs: volumes: /mnt/secrets: source: persistent volume 'vault' can only be mounted in the main container")
+}
+
+func TestInitAndGenerate_with_container_resources(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  example:
+    image: nginx
+    resources:
+      limits:
+        cpu: "1.5"
+        memory: 1Gi
+      requests:
+        cpu: "0.25"
+        memory: 128M
+  logs:
+    image: busybox
+    resources:
+      limits:
+        cpu: "2"
+`), 0755))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"generate", "-o", "app.bicep", "--", "score.yaml",
+	})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(raw), `
+    runtimes: {
+      kubernetes: {
+        pod: {
+          containers: [
+            {
+              name: 'example'
+              resources: {
+                limits: {
+                  cpu: '1500m'
+                  memory: '1Gi'
+                }
+                requests: {
+                  cpu: '250m'
+                  memory: '128M'
+                }
+              }
+            }
+            {
+              name: 'logs'
+              image: 'busybox'
+              resources: {
+                limits: {
+                  cpu: '2'
+                }
+              }
+            }
+          ]
+        }
+      }
+    }
+`)
+}
+
+func TestInitAndGenerate_with_invalid_container_resources(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		resources string
+		expected  string
+	}{
+		{
+			name:      "too precise cpu",
+			resources: "limits: {cpu: \"0.0001\"}",
+			expected:  "limits: cpu: '0.0001' is more precise than 1m",
+		},
+		{
+			name:      "fractional bytes",
+			resources: "requests: {memory: \"0.5\"}",
+			expected:  "requests: memory: '0.5' is not a whole number of bytes",
+		},
+		{
+			name:      "request above limit",
+			resources: "limits: {memory: 1Gi}\n      requests: {memory: 2G}",
+			expected:  "requests: memory: '2G' is greater than the limit '1Gi'",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			td := changeToTempDir(t)
+			_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+			require.NoError(t, err)
+			assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+    resources:
+      `+tc.resources+`
+`), 0755))
+			_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+				"generate", "-o", "app.bicep", "--", "score.yaml",
+			})
+			assert.EqualError(t, err, "invalid score file: score.yaml: containers: main: resources: "+tc.expected)
+		})
+	}
+}
+
+func TestInitAndGenerate_with_secret_variables(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  example:
+    image: nginx
+    variables:
+      DB_HOST: ${resources.db.host}
+      DB_PASSWORD: ${resources.db.password}
+  migrate:
+    image: migrate
+    variables:
+      DB_URL: postgres://${resources.db.username}:${resources.db.password}@${resources.db.host}
+resources:
+  db:
+    type: thing
+`), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, ".score-radius", "thing.provisioners.yaml"), []byte(`
+- uri: template://thing
+  type: thing
+  class: default
+  outputs: |
+    host: ${db.properties.host}
+    username: ${db.properties.username}
+    password: ${db.properties.password}
+  secret_outputs:
+    - password
+`), 0644))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"generate", "-o", "app.bicep", "--", "score.yaml",
+	})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	assert.NoError(t, err)
+	assert.Equal(t, `
+extension radius
+
+@description('The Radius Application ID. Injected automatically by the rad CLI.')
+param application string
+
+@description('The Radius Environment ID. Injected automatically by the rad CLI.')
+param environment string
+
+// Workload 'example' has multiple containers: 'example' is the main container,
+// 'migrate' added as sidecars through the runtimes.kubernetes.pod patch.
+resource example 'Applications.Core/containers@2023-10-01-preview' = {
+  name: 'example'
+  properties: {
+    application: application
+    environment: environment
+    container: {
+      image: 'nginx'
+      env: {
+        DB_HOST: {
+          value: '${db.properties.host}'
+        }
+        DB_PASSWORD: {
+          valueFrom: {
+            secretRef: {
+              source: example_secrets.id
+              key: 'example.env.DB_PASSWORD'
+            }
+          }
+        }
+      }
+    }
+    connections: {
+      db: {
+        source: db.id
+        disableDefaultEnvVars: false
+      }
+    }
+    runtimes: {
+      kubernetes: {
+        pod: {
+          containers: [
+            {
+              name: 'migrate'
+              image: 'migrate'
+              env: [
+                {
+                  name: 'DB_URL'
+                  valueFrom: {
+                    secretKeyRef: {
+                      name: 'example-secrets'
+                      key: 'migrate.env.DB_URL'
+                    }
+                  }
+                }
+              ]
+            }
+          ]
+        }
+      }
+    }
+  }
+  dependsOn: [
+    example_secrets
+  ]
+}
+
+resource example_secrets 'Applications.Core/secretStores@2023-10-01-preview' = {
+  name: 'example-secrets'
+  properties: {
+    application: application
+    type: 'generic'
+    data: {
+      'example.env.DB_PASSWORD': {
+        value: '${db.properties.password}'
+      }
+      'migrate.env.DB_URL': {
+        value: 'postgres://${db.properties.username}:${db.properties.password}@${db.properties.host}'
+      }
+    }
+  }
+}
+`, string(raw))
+
+	sd, ok, err := state.LoadStateDirectory(td)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, []string{"password"}, sd.State.Resources["thing.default#example.db"].Extras.SecretOutputs)
+
+	// the variable names sanitised alike get distinct keys
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  example:
+    image: nginx
+    variables:
+      DB-PASSWORD: ${resources.db.password}
+      DB:PASSWORD: ${resources.db.password}
+resources:
+  db:
+    type: thing
+`), 0755))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"generate", "-o", "app.bicep", "--", "score.yaml",
+	})
+	require.NoError(t, err)
+	raw, err = os.ReadFile(filepath.Join(td, "app.bicep"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(raw), "key: 'example.env.DB-PASSWORD'\n")
+	assert.Contains(t, string(raw), "key: 'example.env.DB-PASSWORD-2'\n")
+	assert.Contains(t, string(raw), "'example.env.DB-PASSWORD': {\n")
+	assert.Contains(t, string(raw), "'example.env.DB-PASSWORD-2': {\n")
+}
+
+func TestInitAndGenerate_with_bicep_escaping(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: my-app
+containers:
+  my-app:
+    image: nginx
+    args: ["-c", "echo '<ok>' && exit 0"]
+    variables:
+      AMPERSAND: a&b
+      URL: https://${resources.my-db.host}/?a=1&b=<2>
+      QUOTED: it's "quoted" \ $${literal}
+    livenessProbe:
+      exec:
+        command: ["test", "-f", "/tmp/ok"]
+resources:
+  my-db:
+    type: thing
+    params:
+      pattern: $${x}
+`), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, ".score-radius", "thing.provisioners.yaml"), []byte(`
+- uri: template://thing
+  type: thing
+  class: default
+  init: |
+    name: {{ splitList "." .Id | last | bicepIdentifier }}
+    tags:
+      team: a&b
+      cost-center: 42
+      ratio: 0.5
+      enabled: true
+      owners: [alice, bob]
+  outputs: |
+    host: {{ print "${" .Init.name ".properties.host}" }}
+  manifests: |
+    resource {{ .Init.name }} 'Applications.Core/extenders@2023-10-01-preview' = {
+      name: {{ bicepLiteralString (splitList "." .Id | last) }}
+      properties: {
+        tags: {{ bicepObject .Init.tags 4 }}
+        params: {{ bicepObject .Params 4 }}
+        endpoints: {{ bicepInterpolatedValue (list (print "https://${" .Init.name ".properties.host}")) 4 }}
+        script: {{ bicepMultilineString "#!/bin/sh\necho 'hello' > /tmp/ok\n" }}
+      }
+    }
+`), 0644))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"generate", "-o", "app.bicep", "--", "score.yaml",
+	})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	assert.NoError(t, err)
+	assert.Equal(t, `
+extension radius
+
+@description('The Radius Application ID. Injected automatically by the rad CLI.')
+param application string
+
+@description('The Radius Environment ID. Injected automatically by the rad CLI.')
+param environment string
+
+resource my_app 'Applications.Core/containers@2023-10-01-preview' = {
+  name: 'my-app'
+  properties: {
+    application: application
+    environment: environment
+    container: {
+      image: 'nginx'
+      args: [
+        '-c'
+        'echo \'<ok>\' && exit 0'
+      ]
+      env: {
+        AMPERSAND: {
+          value: 'a&b'
+        }
+        QUOTED: {
+          value: 'it\'s "quoted" \\ \${literal}'
+        }
+        URL: {
+          value: 'https://${my_db.properties.host}/?a=1&b=<2>'
+        }
+      }
+      livenessProbe: {
+        kind: 'exec'
+        command: 'test -f /tmp/ok'
+      }
+    }
+    connections: {
+      'my-db': {
+        source: my_db.id
+        disableDefaultEnvVars: false
+      }
+    }
+  }
+}
+
+resource my_db 'Applications.Core/extenders@2023-10-01-preview' = {
+  name: 'my-db'
+  properties: {
+    tags: {
+      'cost-center': 42
+      enabled: true
+      owners: [
+        'alice'
+        'bob'
+      ]
+      ratio: json('0.5')
+      team: 'a&b'
+    }
+    params: {
+      pattern: '\${x}'
+    }
+    endpoints: [
+      'https://${my_db.properties.host}'
+    ]
+    script: '''
+#!/bin/sh
+echo 'hello' > /tmp/ok
+'''
+  }
+}`, string(raw))
+}
+
+func TestInitAndGenerate_with_colliding_symbolic_names(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score-a.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: db
+containers:
+  main:
+    image: postgres
+resources:
+  cache:
+    type: thing
+`), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score-b.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: web-app
+containers:
+  main:
+    image: nginx
+    variables:
+      DB: ${resources.db.name}
+resources:
+  db:
+    type: thing
+  cache:
+    type: thing
+`), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, ".score-radius", "thing.provisioners.yaml"), []byte(`
+- uri: template://thing
+  type: thing
+  class: default
+  outputs: |
+    name: {{ print "${" .SymbolicName ".name}" }}
+  manifests: |
+    resource {{ .SymbolicName }} 'Applications.Core/extenders@2023-10-01-preview' = {
+      name: {{ bicepLiteralString .Name }}
+    }
+`), 0644))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"generate", "-o", "app.bicep", "--", "score-a.yaml", "score-b.yaml",
+	})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	assert.NoError(t, err)
+	assert.Equal(t, `
+extension radius
+
+@description('The Radius Application ID. Injected automatically by the rad CLI.')
+param application string
+
+@description('The Radius Environment ID. Injected automatically by the rad CLI.')
+param environment string
+
+resource db 'Applications.Core/containers@2023-10-01-preview' = {
+  name: 'db'
+  properties: {
+    application: application
+    environment: environment
+    container: {
+      image: 'postgres'
+    }
+    connections: {
+      cache: {
+        source: cache.id
+        disableDefaultEnvVars: false
+      }
+    }
+  }
+}
+
+resource web_app 'Applications.Core/containers@2023-10-01-preview' = {
+  name: 'web-app'
+  properties: {
+    application: application
+    environment: environment
+    container: {
+      image: 'nginx'
+      env: {
+        DB: {
+          value: '${web_app_db.name}'
+        }
+      }
+    }
+    connections: {
+      cache: {
+        source: web_app_cache.id
+        disableDefaultEnvVars: false
+      }
+      db: {
+        source: web_app_db.id
+        disableDefaultEnvVars: false
+      }
+    }
+  }
+}
+
+resource cache 'Applications.Core/extenders@2023-10-01-preview' = {
+  name: 'cache'
+}
+resource web_app_cache 'Applications.Core/extenders@2023-10-01-preview' = {
+  name: 'web-app-cache'
+}
+resource web_app_db 'Applications.Core/extenders@2023-10-01-preview' = {
+  name: 'web-app-db'
+}`, string(raw))
+
+	// a new workload never renames the existing symbolic names
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score-c.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: cache
+containers:
+  main:
+    image: redis
+`), 0755))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"generate", "-o", "app.bicep", "--", "score-c.yaml",
+	})
+	require.NoError(t, err)
+	raw, err = os.ReadFile(filepath.Join(td, "app.bicep"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(raw), "resource cache_2 'Applications.Core/containers@2023-10-01-preview' = {\n  name: 'cache'\n")
+	assert.Contains(t, string(raw), "resource cache 'Applications.Core/extenders@2023-10-01-preview' = {\n  name: 'cache'\n")
+	assert.Contains(t, string(raw), "resource web_app 'Applications.Core/containers@2023-10-01-preview'")
+
+	sd, ok, err := state.LoadStateDirectory(td)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "web_app", sd.State.Workloads["web-app"].Extras.SymbolicName)
+	assert.Equal(t, "web_app_db", sd.State.Resources["thing.default#web-app.db"].Extras.SymbolicName)
+}
+
+func TestInitAndGenerate_with_default_provisioners(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+    variables:
+      REDIS_HOST: ${resources.cache.host}
+      REDIS_PASSWORD: ${resources.cache.password}
+      STATE_STORE: ${resources.state.name}
+      BACKEND_URL: ${resources.backend.url}
+      PUBLIC_HOST: ${resources.dns.host}
+service:
+  ports:
+    web:
+      port: 8080
+resources:
+  cache:
+    type: redis
+  state:
+    type: dapr-state-store
+  backend:
+    type: service
+    params:
+      port: 8081
+  dns:
+    type: dns
+  route:
+    type: route
+    params:
+      host: ${resources.dns.host}
+      path: /api
+      port: 8080
+`), 0755))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "backend.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: backend
+containers:
+  main:
+    image: nginx
+service:
+  ports:
+    admin:
+      port: 9090
+    api:
+      port: 8081
+`), 0755))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"generate", "-o", "app.bicep", "--", "score.yaml", "backend.yaml",
+	})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	assert.NoError(t, err)
+	assert.Equal(t, `
+extension radius
+
+@description('The Radius Application ID. Injected automatically by the rad CLI.')
+param application string
+
+@description('The Radius Environment ID. Injected automatically by the rad CLI.')
+param environment string
+
+resource backend 'Applications.Core/containers@2023-10-01-preview' = {
+  name: 'backend'
+  properties: {
+    application: application
+    environment: environment
+    container: {
+      image: 'nginx'
+      ports: {
+        'admin': {
+          port: 9090
+        }
+        'api': {
+          port: 8081
+        }
+      }
+    }
+  }
+}
+
+resource example 'Applications.Core/containers@2023-10-01-preview' = {
+  name: 'example'
+  properties: {
+    application: application
+    environment: environment
+    container: {
+      image: 'nginx'
+      env: {
+        BACKEND_URL: {
+          value: 'http://backend:8081'
+        }
+        PUBLIC_HOST: {
+          value: 'example.localhost'
+        }
+        REDIS_HOST: {
+          value: '${cache.properties.host}'
+        }
+        REDIS_PASSWORD: {
+          valueFrom: {
+            secretRef: {
+              source: example_secrets.id
+              key: 'main.env.REDIS_PASSWORD'
+            }
+          }
+        }
+        STATE_STORE: {
+          value: '${state.properties.componentName}'
+        }
+      }
+      ports: {
+        'web': {
+          port: 8080
+        }
+      }
+    }
+    connections: {
+      backend: {
+        source: 'http://backend:8081'
+        disableDefaultEnvVars: false
+      }
+      cache: {
+        source: cache.id
+        disableDefaultEnvVars: false
+      }
+      state: {
+        source: state.id
+        disableDefaultEnvVars: false
+      }
+    }
+  }
+  dependsOn: [
+    example_secrets
+  ]
+}
+
+resource example_secrets 'Applications.Core/secretStores@2023-10-01-preview' = {
+  name: 'example-secrets'
+  properties: {
+    application: application
+    type: 'generic'
+    data: {
+      'main.env.REDIS_PASSWORD': {
+        value: '${cache.listSecrets().password}'
+      }
+    }
+  }
+}
+
+resource state 'Applications.Dapr/stateStores@2023-10-01-preview' = {
+  name: 'state'
+  properties: {
+    application: application
+    environment: environment
+  }
+}
+resource cache 'Applications.Datastores/redisCaches@2023-10-01-preview' = {
+  name: 'cache'
+  properties: {
+    application: application
+    environment: environment
+  }
+}
+resource route 'Applications.Core/gateways@2023-10-01-preview' = {
+  name: 'route'
+  properties: {
+    application: application
+    hostname: {
+      fullyQualifiedHostname: 'example.localhost'
+    }
+    routes: [
+      {
+        path: '/api'
+        destination: 'http://example:8080'
+      }
+    ]
+  }
+}`, string(raw))
+}
+
+func TestInitAndGenerate_with_default_provisioners_named_alike(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	for _, name := range []string{"w1", "w2"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(td, name+".yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: `+name+`
+containers:
+  main:
+    image: nginx
+resources:
+  db:
+    type: redis
+`), 0755))
+	}
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "w1.yaml", "w2.yaml"})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "resource db 'Applications.Datastores/redisCaches@2023-10-01-preview' = {\n  name: 'db'\n")
+	assert.Contains(t, string(raw), "resource w2_db 'Applications.Datastores/redisCaches@2023-10-01-preview' = {\n  name: 'w2-db'\n")
+}
+
+func TestInitAndGenerate_with_provisioner_state(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+service:
+  ports:
+    web:
+      port: 8080
+resources:
+  db:
+    type: thing
+    metadata:
+      annotations:
+        team: data
+    params:
+      database: ${metadata.name}
+`), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, ".score-radius", "thing.provisioners.yaml"), []byte(`
+- uri: template://thing
+  type: thing
+  class: default
+  params:
+    - database
+  state: |
+    password: {{ dig "password" (randAlphaNum 16) .State | quote }}
+    generation: {{ add1 (dig "generation" 0 .State) }}
+  shared: |
+    things: {{ add1 (dig "things" 0 .Shared) }}
+  outputs: |
+    password: {{ .State.password }}
+  manifests: |
+    // {{ .Uid }} {{ .Type }} {{ .Class }} {{ .Params.database }} {{ .Metadata.annotations.team }}
+    // generation {{ .State.generation }} of {{ .Shared.things }}
+    // {{ range $name, $port := (index .WorkloadServices .SourceWorkload).Ports }}{{ $name }}={{ $port.Port }}{{ end }}
+`), 0644))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "score.yaml"})
+	require.NoError(t, err)
+	sd, ok, err := state.LoadStateDirectory(td)
+	require.NoError(t, err)
+	require.True(t, ok)
+	resState := sd.State.Resources["thing.default#example.db"]
+	password, _ := resState.State["password"].(string)
+	assert.Len(t, password, 16)
+	assert.Equal(t, password, resState.Outputs["password"])
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep"})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(raw), `
+// thing.default#example.db thing default example data
+// generation 2 of 2
+// web=8080`)
+
+	sd, ok, err = state.LoadStateDirectory(td)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"password": password, "generation": 2}, sd.State.Resources["thing.default#example.db"].State)
+	assert.Equal(t, map[string]interface{}{"things": 2}, sd.State.SharedState)
+}
+
+func TestInitAndGenerate_with_cmd_provisioner(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+    variables:
+      DB_HOST: ${resources.db.host}
+resources:
+  db:
+    type: thing
+    params:
+      size: 2
+`), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "provisioner.sh"), []byte(`#!/bin/sh
+cat > "$1"
+cat <<EOF
+{
+  "resource_outputs": {"host": "\${db.properties.host}"},
+  "resource_state": {"key": "value"},
+  "shared_state": {"things": 1},
+  "manifests": "resource db 'Applications.Core/extenders@2023-10-01-preview' = {\n  name: 'db'\n}"
+}
+EOF
+`), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, ".score-radius", "thing.provisioners.yaml"), []byte(`
+- uri: cmd://./provisioner.sh
+  args: ["input.json"]
+  type: thing
+  class: default
+`), 0644))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "score.yaml"})
+	require.NoError(t, err)
+
+	rawInput, err := os.ReadFile(filepath.Join(td, "input.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "resource_uid": "thing.default#example.db",
+  "resource_type": "thing",
+  "resource_class": "default",
+  "resource_id": "example.db",
+  "resource_symbolic_name": "db",
+  "resource_params": {"size": 2},
+  "resource_metadata": null,
+  "resource_state": {},
+  "shared_state": {},
+  "source_workload": "example",
+  "workload_services": {"example": {"service_name": "example", "ports": {}}}
+}`, string(rawInput))
+
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(raw), `
+        DB_HOST: {
+          value: '${db.properties.host}'
+        }`)
+	assert.True(t, strings.HasSuffix(string(raw), `
+resource db 'Applications.Core/extenders@2023-10-01-preview' = {
+  name: 'db'
+}`))
+
+	sd, ok, err := state.LoadStateDirectory(td)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"key": "value"}, sd.State.Resources["thing.default#example.db"].State)
+	assert.Equal(t, map[string]interface{}{"things": 1}, sd.State.SharedState)
+}
+
+func TestInitAndGenerate_with_failing_cmd_provisioner(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+resources:
+  db:
+    type: thing
+`), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, ".score-radius", "thing.provisioners.yaml"), []byte(`
+- uri: cmd://sh
+  args: ["-c", "echo not json"]
+  type: thing
+  class: default
+`), 0644))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "score.yaml"})
+	assert.EqualError(t, err, "failed to provision resources: failed to decode output from cmd provisioner 'cmd://sh': invalid character 'o' in literal null (expecting 'u')")
+}
+
+func TestInitAndGenerate_with_invalid_provisioner_params_and_outputs(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		params   string
+		outputs  string
+		expected string
+	}{
+		{
+			name:     "undeclared param",
+			params:   "{host: example.com, port: 80, size: 2}",
+			outputs:  "url: http://example.com",
+			expected: "resource 'thing.default#example.db': param 'size' is not declared by provisioner 'template://example/thing', expected one of: host, path, port",
+		},
+		{
+			name:     "missing required param",
+			params:   "{host: example.com}",
+			outputs:  "url: http://example.com",
+			expected: "resource 'thing.default#example.db': missing required param 'port' for provisioner 'template://example/thing'",
+		},
+		{
+			name:     "missing expected output",
+			params:   "{host: example.com, port: 80}",
+			outputs:  "other: value",
+			expected: "resource 'thing.default#example.db': provisioner 'template://example/thing' did not produce the expected outputs: url",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			td := changeToTempDir(t)
+			_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+			require.NoError(t, err)
+			assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+resources:
+  db:
+    type: thing
+    params: `+tc.params+`
+`), 0755))
+			assert.NoError(t, os.WriteFile(filepath.Join(td, ".score-radius", "thing.provisioners.yaml"), []byte(`
+- uri: template://example/thing
+  type: thing
+  class: default
+  no_connection: true
+  params:
+    - path
+  required_params:
+    - host
+    - port
+  expected_outputs:
+    - url
+  outputs: |
+    `+tc.outputs+`
+`), 0644))
+			_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+				"generate", "-o", "app.bicep", "--", "score.yaml",
+			})
+			assert.EqualError(t, err, "failed to provision resources: "+tc.expected)
+		})
+	}
+}
+
+func TestInitAndGenerate_with_typed_provisioner_params(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		params   string
+		expected string
+	}{
+		{
+			name:     "defaults",
+			params:   "{host: example.com}",
+			expected: "value: 'http://example.com:80/'",
+		},
+		{
+			name:     "boolean as string",
+			params:   "{host: example.com, tls: \"true\"}",
+			expected: "failed to provision resources: resource 'thing.default#example.db': param 'tls' of provisioner 'template://example/thing': expected type boolean but got the string 'true'",
+		},
+		{
+			name:     "fractional integer",
+			params:   "{host: example.com, port: 80.5}",
+			expected: "failed to provision resources: resource 'thing.default#example.db': param 'port' of provisioner 'template://example/thing': expected type integer but got a number",
+		},
+		{
+			name:     "not allowed",
+			params:   "{host: example.com, path: /other}",
+			expected: "failed to provision resources: resource 'thing.default#example.db': param 'path' of provisioner 'template://example/thing': '/other' is not one of: /, /api",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			td := changeToTempDir(t)
+			_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+			require.NoError(t, err)
+			assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+    variables:
+      URL: ${resources.db.url}
+resources:
+  db:
+    type: thing
+    params: `+tc.params+`
+`), 0755))
+			assert.NoError(t, os.WriteFile(filepath.Join(td, ".score-radius", "thing.provisioners.yaml"), []byte(`
+- uri: template://example/thing
+  type: thing
+  class: default
+  no_connection: true
+  params:
+    host:
+      type: string
+      required: true
+    port:
+      type: integer
+      default: 80
+    path:
+      type: string
+      enum: [/, /api]
+      default: /
+    tls:
+      type: boolean
+      default: false
+  expected_outputs:
+    - url
+  outputs: |
+    url: {{ if .Params.tls }}https{{ else }}http{{ end }}://{{ .Params.host }}:{{ .Params.port }}{{ .Params.path }}
+`), 0644))
+			_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+				"generate", "-o", "app.bicep", "--", "score.yaml",
+			})
+			if strings.HasPrefix(tc.expected, "failed") {
+				assert.EqualError(t, err, tc.expected)
+				return
+			}
+			require.NoError(t, err)
+			raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+			require.NoError(t, err)
+			assert.Contains(t, string(raw), tc.expected)
+		})
+	}
+}
+
+func TestInitAndGenerate_with_provisioner_selectors(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+resources:
+  main-db:
+    type: thing
+    id: main-db
+  cache:
+    type: thing
+    class: large
+  tagged:
+    type: thing
+    metadata:
+      annotations:
+        tier: gold
+  other:
+    type: thing
+`), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score2.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example2
+containers:
+  main:
+    image: nginx
+resources:
+  other:
+    type: thing
+`), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, ".score-radius", "thing.provisioners.yaml"), []byte(`
+- uri: template://any-class
+  type: thing
+  class: "*"
+  no_connection: true
+  manifests: "// {{ .Uid }} by any-class"
+- uri: template://default-class
+  type: thing
+  class: default
+  no_connection: true
+  manifests: "// {{ .Uid }} by default-class"
+- uri: template://main-db
+  type: thing
+  class: "*"
+  id: main-db
+  no_connection: true
+  manifests: "// {{ .Uid }} by main-db"
+- uri: template://gold
+  type: thing
+  class: default
+  metadata_selector:
+    annotations:
+      tier: gold
+  no_connection: true
+  manifests: "// {{ .Uid }} by gold"
+- uri: template://example2
+  type: thing
+  class: default
+  workloads: [example2]
+  no_connection: true
+  manifests: "// {{ .Uid }} by example2"
+`), 0644))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "score.yaml", "score2.yaml"})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	require.NoError(t, err)
+	for _, line := range []string{
+		"// thing.default#main-db by main-db",
+		"// thing.large#example.cache by any-class",
+		"// thing.default#example.tagged by gold",
+		"// thing.default#example.other by default-class",
+		"// thing.default#example2.other by example2",
+	} {
+		assert.Contains(t, string(raw)+"\n", line+"\n")
+	}
+
+	sd, ok, err := state.LoadStateDirectory(td)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "template://main-db", sd.State.Resources["thing.default#main-db"].ProvisionerUri)
+	assert.Equal(t, "type 'thing', id 'main-db', any class", sd.State.Resources["thing.default#main-db"].Extras.ProvisionerMatch)
+	assert.Equal(t, "type 'thing', class 'default', metadata annotations", sd.State.Resources["thing.default#example.tagged"].Extras.ProvisionerMatch)
+	assert.Equal(t, "type 'thing', class 'default', workload 'example2'", sd.State.Resources["thing.default#example2.other"].Extras.ProvisionerMatch)
+}
+
+func TestInitAndGenerate_with_frozen_provisioners(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init"})
+	require.NoError(t, err)
+
+	sourcePath := filepath.Join(td, "things.yaml")
+	assert.NoError(t, os.WriteFile(sourcePath, []byte(`
+- uri: template://thing
+  type: thing
+  class: default
+`), 0644))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "add", sourcePath})
+	require.NoError(t, err)
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "--frozen", "--", "score.yaml"})
+	require.NoError(t, err)
+
+	// a hand-written provisioners file is not locked by generate, only by provisioners update
+	localPath := filepath.Join(td, ".score-radius", "local.provisioners.yaml")
+	assert.NoError(t, os.WriteFile(localPath, []byte("[]\n"), 0644))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "--frozen"})
+	assert.EqualError(t, err, "provisioners don't match the lock: provisioners file 'local.provisioners.yaml' is not locked")
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate"})
+	require.NoError(t, err)
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "--frozen"})
+	assert.EqualError(t, err, "provisioners don't match the lock: provisioners file 'local.provisioners.yaml' is not locked")
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "update"})
+	require.NoError(t, err)
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "--frozen"})
+	require.NoError(t, err)
+
+	// an installed file is only locked again by provisioners update
+	lock, err := loader.LoadLock(filepath.Join(td, ".score-radius"))
+	require.NoError(t, err)
+	installedName := lock.Sources[0].Files[0].Name
+	installedPath := filepath.Join(td, ".score-radius", installedName)
+	raw, err := os.ReadFile(installedPath)
+	require.NoError(t, err)
+	assert.NoError(t, os.WriteFile(installedPath, append(raw, []byte("  description: changed\n")...), 0644))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate"})
+	require.NoError(t, err)
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "--frozen"})
+	assert.EqualError(t, err, "provisioners don't match the lock: provisioners file '"+installedName+"' does not match its locked digest")
+
+	assert.NoError(t, os.Remove(localPath))
+	assert.NoError(t, os.WriteFile(installedPath, raw, 0644))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "--frozen"})
+	assert.EqualError(t, err, "provisioners don't match the lock: locked provisioners file 'local.provisioners.yaml' does not exist")
+}
+
+func TestInitAndGenerate_with_recipe_provisioner(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+    variables:
+      CACHE_HOST: ${resources.cache.host}
+      CACHE_PASSWORD: ${resources.cache.password}
+resources:
+  cache:
+    type: redis
+    class: large
+    params:
+      sku: premium
+      replicas: 2
+`), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, ".score-radius", "recipes.provisioners.yaml"), []byte(`
+- uri: recipe://platform/redis-large
+  type: redis
+  class: large
+  portable_type: Applications.Datastores/redisCaches
+  recipe: large-redis
+  params:
+    sku:
+      type: string
+      enum: [standard, premium]
+    replicas:
+      type: integer
+    disableDefaultEnvVars:
+      type: boolean
+      default: true
+  expected_outputs: [host, password]
+  secret_outputs: [password]
+`), 0644))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "score.yaml"})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), `
+        CACHE_HOST: {
+          value: '${cache.properties.host}'
+        }`)
+	assert.Contains(t, string(raw), `
+      'main.env.CACHE_PASSWORD': {
+        value: '${cache.listSecrets().password}'
+      }`)
+	assert.Contains(t, string(raw), `
+        source: cache.id
+        disableDefaultEnvVars: true`)
+	assert.True(t, strings.HasSuffix(string(raw), `
+resource cache 'Applications.Datastores/redisCaches@2023-10-01-preview' = {
+  name: 'cache'
+  properties: {
+    application: application
+    environment: environment
+    recipe: {
+      name: 'large-redis'
+      parameters: {
+        replicas: 2
+        sku: 'premium'
+      }
+    }
+  }
+}`), string(raw))
+
+	// the resources named alike in two workloads get the unique Radius names of their symbolic names
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "other.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: other
+containers:
+  main:
+    image: nginx
+resources:
+  cache:
+    type: redis
+    class: large
+`), 0755))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "other.yaml"})
+	require.NoError(t, err)
+	raw, err = os.ReadFile(filepath.Join(td, "app.bicep"))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "resource other_cache 'Applications.Datastores/redisCaches@2023-10-01-preview' = {\n  name: 'other-cache'\n")
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, ".score-radius", "recipes.provisioners.yaml"), []byte(`
+- uri: recipe://platform/redis-large
+  type: redis
+  class: large
+`), 0644))
+	stdout, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "validate", "--format", "json"})
+	assert.EqualError(t, err, "found 1 errors and 0 warnings in the provisioners files")
+	assert.Contains(t, stdout, "provisioner 'recipe://platform/redis-large': recipe provisioner requires a portable_type, e.g. Applications.Datastores/redisCaches")
+}
+
+func TestInitAndGenerate_with_extender_fallback(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+    variables:
+      QUEUE_URL: ${resources.jobs.url}
+      QUEUE_REGION: ${resources.jobs.config.region}
+      CACHE_HOST: ${resources.cache.host}
+resources:
+  jobs:
+    type: sqs-queue
+    params:
+      retention: 4d
+      fifo: true
+  cache:
+    type: redis
+`), 0755))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "score.yaml"})
+	assert.ErrorContains(t, err, "resource 'sqs-queue.default#example.jobs' is not supported by any provisioner")
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--extender-fallback", "--", "score.yaml"})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), `
+        QUEUE_REGION: {
+          value: '${jobs.properties.config.region}'
+        }
+        QUEUE_URL: {
+          value: '${jobs.properties.url}'
+        }`)
+	assert.Contains(t, string(raw), `
+      jobs: {
+        source: jobs.id
+        disableDefaultEnvVars: false
+      }`)
+	assert.Contains(t, string(raw), `
+resource jobs 'Applications.Core/extenders@2023-10-01-preview' = {
+  name: 'jobs'
+  properties: {
+    application: application
+    environment: environment
+    fifo: true
+    retention: '4d'
+    recipe: {
+      name: 'sqs-queue'
+    }
+  }
+}`)
+	// the resources with a provisioner are not affected
+	assert.Contains(t, string(raw), "resource cache 'Applications.Datastores/redisCaches@2023-10-01-preview' = {")
+
+	sd, ok, err := state.LoadStateDirectory(td)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "extender://fallback", sd.State.Resources["sqs-queue.default#example.jobs"].ProvisionerUri)
+	assert.Equal(t, "type 'sqs-queue', fallback", sd.State.Resources["sqs-queue.default#example.jobs"].Extras.ProvisionerMatch)
+
+	// the extenders of the resources named alike in two workloads get the unique Radius names of their symbolic names
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "other.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: other
+containers:
+  main:
+    image: nginx
+resources:
+  jobs:
+    type: sqs-queue
+`), 0755))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--extender-fallback", "--", "other.yaml"})
+	require.NoError(t, err)
+	raw, err = os.ReadFile(filepath.Join(td, "app.bicep"))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "resource other_jobs 'Applications.Core/extenders@2023-10-01-preview' = {\n  name: 'other-jobs'\n")
+}
+
+func TestInitAndGenerate_with_service_connections(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "backend.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: backend
+containers:
+  main:
+    image: nginx
+service:
+  ports:
+    web:
+      port: 8080
+`), 0755))
+	writeFrontend := func(params string) {
+		assert.NoError(t, os.WriteFile(filepath.Join(td, "frontend.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: frontend
+containers:
+  main:
+    image: nginx
+    variables:
+      API_URL: ${resources.api.url}
+resources:
+  api:
+    type: service
+    params:
+`+params), 0755))
+	}
+
+	writeFrontend("      workload: backend\n")
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "backend.yaml", "frontend.yaml"})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), `
+        API_URL: {
+          value: 'http://backend:8080'
+        }`)
+	assert.Contains(t, string(raw), `
+    connections: {
+      api: {
+        source: 'http://backend:8080'
+        disableDefaultEnvVars: false
+      }
+    }`)
+
+	writeFrontend("      workload: backend\n      port: 9090\n")
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "backend.yaml", "frontend.yaml"})
+	assert.ErrorContains(t, err, "workload 'backend' does not publish port 9090")
+
+	writeFrontend("      workload: payments\n")
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "backend.yaml", "frontend.yaml"})
+	assert.ErrorContains(t, err, "workload 'payments' does not exist")
+}
+
+func TestInitAndGenerate_with_routes_merged_per_host(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "frontend.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: frontend
+containers:
+  main:
+    image: nginx
+service:
+  ports:
+    web:
+      port: 8080
+resources:
+  dns:
+    type: dns
+    id: public-dns
+    params:
+      host: shop.example.com
+  route:
+    type: route
+    params:
+      host: ${resources.dns.host}
+      port: 8080
+  admin-route:
+    type: route
+    params:
+      host: admin.example.com
+      port: 8080
+`), 0755))
+	writeBackend := func(path string, port int) {
+		assert.NoError(t, os.WriteFile(filepath.Join(td, "backend.yaml"), []byte(fmt.Sprintf(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: backend
+containers:
+  main:
+    image: nginx
+service:
+  ports:
+    api:
+      port: 8081
+resources:
+  dns:
+    type: dns
+    id: public-dns
+  route:
+    type: route
+    params:
+      host: ${resources.dns.host}
+      path: %s
+      port: %d
+`, path, port)), 0755))
+	}
+
+	writeBackend("/api", 8081)
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "frontend.yaml", "backend.yaml"})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(raw), "'Applications.Core/gateways@2023-10-01-preview'"))
+	assert.Contains(t, string(raw), `
+resource route 'Applications.Core/gateways@2023-10-01-preview' = {
+  name: 'route'
+  properties: {
+    application: application
+    hostname: {
+      fullyQualifiedHostname: 'shop.example.com'
+    }
+    routes: [
+      {
+        path: '/api'
+        destination: 'http://backend:8081'
+      }
+      {
+        path: '/'
+        destination: 'http://frontend:8080'
+      }
+    ]
+  }
+}`)
+	assert.Contains(t, string(raw), `
+resource admin_route 'Applications.Core/gateways@2023-10-01-preview' = {
+  name: 'admin-route'
+  properties: {
+    application: application
+    hostname: {
+      fullyQualifiedHostname: 'admin.example.com'
+    }
+    routes: [
+      {
+        path: '/'
+        destination: 'http://frontend:8080'
+      }
+    ]
   }
+}`)
+
+	writeBackend("/", 8081)
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "frontend.yaml", "backend.yaml"})
+	assert.ErrorContains(t, err, "path '/' of host 'shop.example.com' is routed by both backend.route and frontend.route")
+
+	writeBackend("/api", 9090)
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "frontend.yaml", "backend.yaml"})
+	assert.ErrorContains(t, err, "workload 'backend' does not publish port 9090")
 }
 
+func TestInitAndGenerate_with_environment_resource(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+    variables:
+      LOG_LEVEL: ${resources.env.LOG_LEVEL}
+      REGION: ${resources.env.REGION}
+      WORKERS: ${resources.env.WORKERS}
+      DEBUG: ${resources.env.DEBUG}
+      DB_URL: postgres://app:${resources.env.DB_PASSWORD}@${resources.env.DB_HOST}/app
+resources:
+  env:
+    type: environment
+    params:
+      secure: [DB_PASSWORD]
+`), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "values.yaml"), []byte(`
+DB_HOST: db.internal
+DB_PASSWORD: not-written-either
+LOG_LEVEL: debug
+WORKERS: 4
+DEBUG: true
+`), 0644))
+	t.Setenv("REGION", "westeurope")
+	t.Setenv("DB_PASSWORD", "not-written")
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--environment-values", "values.yaml", "--", "score.yaml"})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "app.bicep"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(raw), `
+extension radius
+
+@description('The Radius Application ID. Injected automatically by the rad CLI.')
+param application string
+
+@description('The Radius Environment ID. Injected automatically by the rad CLI.')
+param environment string
+
+@description('The DB_HOST environment value.')
+param DB_HOST string
+
+@secure()
+@description('The DB_PASSWORD environment value.')
+param DB_PASSWORD string
+
+@description('The DEBUG environment value.')
+param DEBUG bool
+
+@description('The LOG_LEVEL environment value.')
+param LOG_LEVEL string
+
+@description('The REGION environment value.')
+param REGION string
+
+@description('The WORKERS environment value.')
+param WORKERS int
+
+resource example `), string(raw))
+	assert.Contains(t, string(raw), `
+        LOG_LEVEL: {
+          value: '${LOG_LEVEL}'
+        }`)
+	assert.Contains(t, string(raw), `
+      'main.env.DB_URL': {
+        value: 'postgres://app:${DB_PASSWORD}@${DB_HOST}/app'
+      }`)
+	assert.NotContains(t, string(raw), "connections")
+
+	raw, err = os.ReadFile(filepath.Join(td, "app.bicepparam"))
+	require.NoError(t, err)
+	assert.Equal(t, `using 'app.bicep'
+
+param DB_HOST = 'db.internal'
+param DB_PASSWORD = readEnvironmentVariable('DB_PASSWORD')
+param DEBUG = true
+param LOG_LEVEL = 'debug'
+param REGION = 'westeurope'
+param WORKERS = 4
 `, string(raw))
+	assert.NotContains(t, string(raw), "not-written")
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+    variables:
+      ENV: ${resources.env.environment}
+resources:
+  env:
+    type: environment
+`), 0755))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "score.yaml"})
+	assert.ErrorContains(t, err, "environment resource 'example.env': output 'environment' is a reserved Bicep param")
+
+	// the params file of the previous generation is removed once the Bicep file has no environment params
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+`), 0755))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "score.yaml"})
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(td, "app.bicepparam"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestInitAndGenerate_with_environment_output_colliding_with_symbolic_name(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	for _, output := range []string{"redis", "example", "example_secrets"} {
+		t.Run(output, func(t *testing.T) {
+			assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+    variables:
+      VALUE: ${resources.env.`+output+`}
+      REDIS_HOST: ${resources.redis.host}
+resources:
+  env:
+    type: environment
+  redis:
+    type: redis
+`), 0755))
+			_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "score.yaml"})
+			assert.ErrorContains(t, err, "environment resource 'example.env': output '"+output+"' is already the Bicep symbolic name of a workload or resource")
+		})
+	}
+}
+
+func TestInitAndGenerate_with_environment_output_colliding_with_shared_manifests(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(td, ".score-radius", "thing.provisioners.yaml"), []byte(`
+- uri: template://thing
+  type: thing
+  class: default
+  params: [value]
+  no_connection: true
+  shared_manifests: |
+    var shared_thing = 'thing'
+`), 0644))
+
+	// the output is looked up after the shared manifests are generated
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+    variables:
+      VALUE: ${resources.env.shared_thing}
+resources:
+  env:
+    type: environment
+  thing:
+    type: thing
+`), 0755))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "score.yaml"})
+	assert.ErrorContains(t, err, "environment resource 'example.env': output 'shared_thing' is already the Bicep symbolic name of a workload or resource")
+
+	// the output is looked up by the params of a resource, before the shared manifests are generated
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "score.yaml"), []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+resources:
+  env:
+    type: environment
+  thing:
+    type: thing
+    params:
+      value: ${resources.env.shared_thing}
+`), 0755))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "-o", "app.bicep", "--", "score.yaml"})
+	assert.ErrorContains(t, err, "provisioner 'template://thing' shared manifests: 'shared_thing' is already the Bicep param of an environment output")
 }
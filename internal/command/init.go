@@ -20,21 +20,23 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 
 	"github.com/score-spec/score-go/framework"
 	scoretypes "github.com/score-spec/score-go/types"
-	"github.com/score-spec/score-go/uriget"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
+	"github.com/score-spec/score-radius/internal/provisioners/defaults"
 	"github.com/score-spec/score-radius/internal/provisioners/loader"
 	"github.com/score-spec/score-radius/internal/state"
 )
 
 const (
-	initCmdFileFlag         = "file"
-	initCmdFileNoSampleFlag = "no-sample"
-	initCmdProvisionersFlag = "provisioners"
+	initCmdFileFlag                  = "file"
+	initCmdFileNoSampleFlag          = "no-sample"
+	initCmdProvisionersFlag          = "provisioners"
+	initCmdNoDefaultProvisionersFlag = "no-default-provisioners"
 )
 
 var initCmd = &cobra.Command{
@@ -69,6 +71,19 @@ var initCmd = &cobra.Command{
 			}
 		}
 
+		defaultProvisionersPath := filepath.Join(sd.Path, defaults.FileName)
+		if v, _ := cmd.Flags().GetBool(initCmdNoDefaultProvisionersFlag); v {
+			if err := os.Remove(defaultProvisionersPath); err == nil {
+				slog.Info("Removed default provisioners", "file", defaultProvisionersPath)
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to remove default provisioners: %w", err)
+			}
+		} else if err := os.WriteFile(defaultProvisionersPath, defaults.Provisioners, 0644); err != nil {
+			return fmt.Errorf("failed to write default provisioners: %w", err)
+		} else {
+			slog.Info("Wrote default provisioners", "file", defaultProvisionersPath)
+		}
+
 		initCmdScoreFile, _ := cmd.Flags().GetString(initCmdFileFlag)
 		if _, err := os.Stat(initCmdScoreFile); err != nil {
 			if v, _ := cmd.Flags().GetBool(initCmdFileNoSampleFlag); v {
@@ -108,23 +123,20 @@ var initCmd = &cobra.Command{
 			slog.Info("Skipping creation of initial Score file since it already exists", "file", initCmdScoreFile)
 		}
 
+		lock, err := loader.LoadLock(sd.Path)
+		if err != nil {
+			return err
+		}
 		if v, _ := cmd.Flags().GetStringArray(initCmdProvisionersFlag); len(v) > 0 {
 			for i, vi := range v {
-				files, err := uriget.GetFiles(cmd.Context(), vi)
-				if err != nil {
-					return fmt.Errorf("failed to load provisioners %d: %w", i+1, err)
-				}
-				for _, f := range files {
-					saveFilename := f.URI
-					if saveFilename == "-" {
-						saveFilename = "from-stdin.provisioners.yaml"
-					}
-					if err := loader.SaveProvisionerToDirectory(sd.Path, saveFilename, f.Content); err != nil {
-						return fmt.Errorf("failed to save provisioner from %s: %w", f.URI, err)
-					}
+				if err := lock.Install(cmd.Context(), sd.Path, vi); err != nil {
+					return fmt.Errorf("failed to install provisioners %d: %w", i+1, err)
 				}
 			}
 		}
+		if err := lock.Persist(sd.Path); err != nil {
+			return err
+		}
 
 		return nil
 	},
@@ -134,5 +146,6 @@ func init() {
 	initCmd.Flags().StringP(initCmdFileFlag, "f", "score.yaml", "The score file to initialize")
 	initCmd.Flags().Bool(initCmdFileNoSampleFlag, false, "Disable generation of the sample score file")
 	initCmd.Flags().StringArray(initCmdProvisionersFlag, nil, "Provisioner files to install. May be specified multiple times. Supports URI retrieval.")
+	initCmd.Flags().Bool(initCmdNoDefaultProvisionersFlag, false, "Disable the installation of the default provisioners")
 	rootCmd.AddCommand(initCmd)
 }
@@ -17,6 +17,7 @@ package command
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -24,6 +25,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/score-spec/score-radius/internal/provisioners/defaults"
 	"github.com/score-spec/score-radius/internal/state"
 )
 
@@ -125,3 +127,37 @@ func TestInitNominal_run_twice(t *testing.T) {
 		assert.Equal(t, map[string]interface{}{}, sd.State.SharedState)
 	}
 }
+
+func TestInitNoDefaultProvisioners(t *testing.T) {
+	td := t.TempDir()
+
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(td))
+	defer func() {
+		require.NoError(t, os.Chdir(wd))
+	}()
+
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(state.DefaultRelativeStateDirectory, defaults.FileName))
+	assert.NoError(t, err)
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample", "--no-default-provisioners"})
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(state.DefaultRelativeStateDirectory, defaults.FileName))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	assert.NoError(t, os.WriteFile("score.yaml", []byte(`
+apiVersion: score.dev/v1b1
+metadata:
+  name: example
+containers:
+  main:
+    image: nginx
+resources:
+  cache:
+    type: redis
+`), 0755))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"generate", "score.yaml"})
+	assert.EqualError(t, err, "failed to provision resources: resource 'redis.default#example.cache' is not supported by any provisioner. Please implement a custom resource provisioner to support this resource type 'redis' with class 'default'")
+}
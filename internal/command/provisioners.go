@@ -17,8 +17,11 @@ package command
 import (
 	"fmt"
 	"github.com/spf13/cobra"
+	"io"
 	"log/slog"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/score-spec/score-radius/internal/provisioners"
@@ -43,8 +46,116 @@ after 'init' has been run. The list of resources provisioners will be empty if n
 		SilenceErrors: true,
 		RunE:          listProvisioners,
 	}
+	provisionersDescribe = &cobra.Command{
+		Use:   "describe TYPE [--class CLASS] [--format table|json]",
+		Short: "Describe the provisioners of a resource type",
+		Long: `The describe command shows the provisioners of a resource type, with the schema of their params and their
+outputs. This requires an active score-radius state after 'init' has been run.
+`,
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		RunE:          describeProvisioners,
+	}
+	provisionersAdd = &cobra.Command{
+		Use:   "add URI...",
+		Short: "Install provisioners files from uris",
+		Long: `The add command fetches the provisioners files from each uri, saves them to the state directory, and records
+the uri so that the files can be updated or removed later. The uris support the same schemes as 'init --provisioners'.
+`,
+		Args:          cobra.MinimumNArgs(1),
+		SilenceErrors: true,
+		RunE:          addProvisioners,
+	}
+	provisionersRemove = &cobra.Command{
+		Use:           "remove URI...",
+		Short:         "Remove the provisioners files installed from uris",
+		Args:          cobra.MinimumNArgs(1),
+		SilenceErrors: true,
+		RunE:          removeProvisioners,
+	}
+	provisionersUpdate = &cobra.Command{
+		Use:   "update [URI...]",
+		Short: "Fetch the installed provisioners files again",
+		Long: `The update command fetches the provisioners files again from each recorded uri, or only from the given uris,
+and replaces the installed files. The provisioners read from the standard input can't be updated. The digests of
+the local provisioners files are locked again.
+`,
+		Args:          cobra.ArbitraryArgs,
+		SilenceErrors: true,
+		RunE:          updateProvisioners,
+	}
 )
 
+// loadProvisionersLock loads the state directory and the lock of its provisioners files.
+func loadProvisionersLock() (*state.StateDirectory, *loader.Lock, error) {
+	sd, ok, err := state.LoadStateDirectory(".")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load existing state directory: %w", err)
+	} else if !ok {
+		return nil, nil, fmt.Errorf("no state directory found, run 'score-radius init' first")
+	}
+	lock, err := loader.LoadLock(sd.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sd, lock, nil
+}
+
+func addProvisioners(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	sd, lock, err := loadProvisionersLock()
+	if err != nil {
+		return err
+	}
+	for _, uri := range args {
+		if err := lock.Install(cmd.Context(), sd.Path, uri); err != nil {
+			return err
+		}
+	}
+	return lock.Persist(sd.Path)
+}
+
+func removeProvisioners(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	sd, lock, err := loadProvisionersLock()
+	if err != nil {
+		return err
+	}
+	for _, uri := range args {
+		if err := lock.Remove(sd.Path, uri); err != nil {
+			return err
+		}
+	}
+	return lock.Persist(sd.Path)
+}
+
+func updateProvisioners(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	sd, lock, err := loadProvisionersLock()
+	if err != nil {
+		return err
+	}
+	uris := args
+	if len(uris) == 0 {
+		for _, source := range lock.Sources {
+			uris = append(uris, source.Uri)
+		}
+	}
+	for _, uri := range uris {
+		if lock.Get(uri) == nil {
+			return fmt.Errorf("no provisioners installed from '%s'", uri)
+		} else if uri == loader.StdinUri {
+			slog.Warn("Skipping the provisioners read from the standard input")
+			continue
+		}
+		if err := lock.Install(cmd.Context(), sd.Path, uri); err != nil {
+			return err
+		}
+		slog.Info(fmt.Sprintf("Updated provisioners from %s", uri))
+	}
+	return lock.Persist(sd.Path)
+}
+
 func listProvisioners(cmd *cobra.Command, args []string) error {
 	cmd.SilenceUsage = true
 	sd, ok, err := state.LoadStateDirectory(".")
@@ -64,12 +175,18 @@ func listProvisioners(cmd *cobra.Command, args []string) error {
 		slog.Info("No resources provisioners found")
 		return nil
 	}
+	provisioners = append(provisioners, builtInProvisioners()...)
+
+	lock, err := loader.LoadLock(sd.Path)
+	if err != nil {
+		return err
+	}
 
 	outputFormat := cmd.Flag("format").Value.String()
-	return displayProvisioners(provisioners, outputFormat)
+	return displayProvisioners(cmd.OutOrStdout(), provisioners, lock, outputFormat)
 }
 
-func displayProvisioners(provisioners []provisioners.Provisioner, outputFormat string) error {
+func displayProvisioners(out io.Writer, provisioners []provisioners.Provisioner, lock *loader.Lock, outputFormat string) error {
 	var outputFormatter formatter.OutputFormatter
 	sortedProvisioners := sortProvisionersByType(provisioners)
 
@@ -81,32 +198,171 @@ func displayProvisioners(provisioners []provisioners.Provisioner, outputFormat s
 			Params      []string
 			Outputs     []string
 			Description string
+			File        string
+			Source      string `json:",omitempty"`
 		}
 		var outputs []jsonData
 		for _, provisioner := range sortedProvisioners {
 			outputs = append(outputs, jsonData{
 				Type:        provisioner.ResType,
 				Class:       provisioner.Class,
-				Params:      provisioner.Params,
+				Params:      provisioner.EffectiveParams().Names(),
 				Outputs:     provisioner.Outputs,
 				Description: provisioner.Description,
+				File:        provisioner.SourceFile,
+				Source:      lock.FileSource(provisioner.SourceFile),
 			})
 		}
-		outputFormatter = &formatter.JSONOutputFormatter[[]jsonData]{Data: outputs}
+		outputFormatter = &formatter.JSONOutputFormatter[[]jsonData]{Data: outputs, Out: out}
 	default:
 		rows := [][]string{}
 		for _, provisioner := range sortedProvisioners {
-			rows = append(rows, []string{provisioner.ResType, provisioner.Class, strings.Join(provisioner.Params, ", "), strings.Join(provisioner.Outputs, ", "), provisioner.Description})
+			schemas := provisioner.EffectiveParams()
+			params := make([]string, 0, len(schemas))
+			for _, name := range schemas.Names() {
+				params = append(params, formatParam(name, schemas[name]))
+			}
+			source := provisioner.SourceFile
+			if source == "" {
+				source = "(built-in)"
+			} else if uri := lock.FileSource(provisioner.SourceFile); uri != "" {
+				source = fmt.Sprintf("%s (%s)", provisioner.SourceFile, uri)
+			}
+			rows = append(rows, []string{provisioner.ResType, provisioner.Class, strings.Join(params, ", "), strings.Join(provisioner.Outputs, ", "), provisioner.Description, source})
 		}
-		headers := []string{"Type", "Class", "Params", "Outputs", "Description"}
+		headers := []string{"Type", "Class", "Params", "Outputs", "Description", "Source"}
 		outputFormatter = &formatter.TableOutputFormatter{
 			Headers: headers,
 			Rows:    rows,
+			Out:     out,
 		}
 	}
 	return outputFormatter.Display()
 }
 
+// builtInProvisioners returns the provisioners which are not loaded from the provisioners files, listed and documented
+// like the others. The extender fallback is not, since it only applies with 'generate --extender-fallback'.
+func builtInProvisioners() []provisioners.Provisioner {
+	return []provisioners.Provisioner{provisioners.NewEnvironmentParams().Provisioner()}
+}
+
+// formatParam formats a param and the short form of its schema, e.g. 'port (integer, required)'.
+func formatParam(name string, schema provisioners.ParamSchema) string {
+	details := make([]string, 0, 3)
+	if schema.Type != "" {
+		details = append(details, schema.Type)
+	}
+	if schema.Required {
+		details = append(details, "required")
+	}
+	if schema.Default != nil {
+		details = append(details, fmt.Sprintf("default %v", schema.Default))
+	}
+	if len(details) == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, strings.Join(details, ", "))
+}
+
+func describeProvisioners(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	sd, ok, err := state.LoadStateDirectory(".")
+	if err != nil {
+		return fmt.Errorf("failed to load existing state directory: %w", err)
+	} else if !ok {
+		return fmt.Errorf("no state directory found, run 'score-radius init' first")
+	}
+
+	allProvisioners, err := loader.LoadProvisionersFromDirectory(sd.Path, loader.ProvisionersFileSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to load resources provisioners in %s: %w", sd.Path, err)
+	}
+	allProvisioners = append(allProvisioners, builtInProvisioners()...)
+	class := cmd.Flag("class").Value.String()
+	matching := slices.DeleteFunc(allProvisioners, func(provisioner provisioners.Provisioner) bool {
+		return provisioner.ResType != args[0] || (class != "" && provisioner.Class != class && provisioner.Class != provisioners.AnyClass)
+	})
+	if len(matching) == 0 {
+		return fmt.Errorf("no provisioner found for resource type '%s'", args[0])
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].Class < matching[j].Class
+	})
+
+	if cmd.Flag("format").Value.String() == "json" {
+		type jsonData struct {
+			Uri              string
+			Type             string
+			Class            string
+			Id               string                 `json:",omitempty"`
+			Workloads        []string               `json:",omitempty"`
+			MetadataSelector map[string]interface{} `json:",omitempty"`
+			Description      string
+			Params           provisioners.ParamsSchema
+			Outputs          []string
+			SecretOutputs    []string
+		}
+		outputs := make([]jsonData, 0, len(matching))
+		for _, provisioner := range matching {
+			outputs = append(outputs, jsonData{
+				Uri:              provisioner.Uri,
+				Type:             provisioner.ResType,
+				Class:            provisioner.Class,
+				Id:               provisioner.ResId,
+				Workloads:        provisioner.Workloads,
+				MetadataSelector: provisioner.MetadataSelector,
+				Description:      provisioner.Description,
+				Params:           provisioner.EffectiveParams(),
+				Outputs:          provisioner.Outputs,
+				SecretOutputs:    provisioner.SecretOutputs,
+			})
+		}
+		return (&formatter.JSONOutputFormatter[[]jsonData]{Data: outputs, Out: cmd.OutOrStdout()}).Display()
+	}
+
+	for i, provisioner := range matching {
+		if i > 0 {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout())
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Uri: %s\nType: %s\nClass: %s\n", provisioner.Uri, provisioner.ResType, provisioner.Class)
+		if provisioner.ResId != "" {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Id: %s\n", provisioner.ResId)
+		}
+		if len(provisioner.Workloads) > 0 {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Workloads: %s\n", strings.Join(provisioner.Workloads, ", "))
+		}
+		if len(provisioner.MetadataSelector) > 0 {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Metadata selector: %v\n", provisioner.MetadataSelector)
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Description: %s\nOutputs: %s\n", provisioner.Description, strings.Join(provisioner.Outputs, ", "))
+		schemas := provisioner.EffectiveParams()
+		if len(schemas) == 0 {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Params: any")
+			continue
+		}
+		rows := [][]string{}
+		for _, name := range schemas.Names() {
+			schema := schemas[name]
+			var defaultValue, allowedValues string
+			if schema.Default != nil {
+				defaultValue = fmt.Sprint(schema.Default)
+			}
+			if len(schema.Enum) > 0 {
+				allowedValues = strings.Trim(fmt.Sprint(schema.Enum), "[]")
+			}
+			rows = append(rows, []string{name, schema.Type, strconv.FormatBool(schema.Required), defaultValue, allowedValues, schema.Description})
+		}
+		if err := (&formatter.TableOutputFormatter{
+			Headers: []string{"Param", "Type", "Required", "Default", "Allowed", "Description"},
+			Rows:    rows,
+			Out:     cmd.OutOrStdout(),
+		}).Display(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func sortProvisionersByType(provisioners []provisioners.Provisioner) []provisioners.Provisioner {
 	sort.Slice(provisioners, func(i, j int) bool {
 		return provisioners[i].ResType < provisioners[j].ResType
@@ -117,5 +373,11 @@ func sortProvisionersByType(provisioners []provisioners.Provisioner) []provision
 func init() {
 	provisionersList.Flags().StringP("format", "f", "table", "Format of the output: table (default), json")
 	provisionersGroup.AddCommand(provisionersList)
+	provisionersDescribe.Flags().String("class", "", "Only describe the provisioner of this resource class")
+	provisionersDescribe.Flags().StringP("format", "f", "table", "Format of the output: table (default), json")
+	provisionersGroup.AddCommand(provisionersDescribe)
+	provisionersGroup.AddCommand(provisionersAdd)
+	provisionersGroup.AddCommand(provisionersRemove)
+	provisionersGroup.AddCommand(provisionersUpdate)
 	rootCmd.AddCommand(provisionersGroup)
-}
\ No newline at end of file
+}
@@ -0,0 +1,375 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/score-spec/score-radius/internal/provisioners"
+	"github.com/score-spec/score-radius/internal/provisioners/loader"
+	"github.com/score-spec/score-radius/internal/state"
+)
+
+const (
+	provisionersDocsOutputFlag = "output"
+	provisionersDocsFormatFlag = "format"
+)
+
+var provisionersDocs = &cobra.Command{
+	Use:   "docs [--output DIR] [--format markdown|html]",
+	Short: "Generate the documentation of the resource types of the provisioners",
+	Long: `The docs command writes a page per resource type of the provisioners of the state directory, and an index page.
+Each page lists the classes of the type with their params, outputs, the Radius resource types they declare, and an
+example of the Score resource.
+`,
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	RunE:          generateProvisionersDocs,
+}
+
+// docsType is the documentation of a resource type.
+type docsType struct {
+	Type     string
+	Page     string
+	Classes  []docsClass
+	Summary  string
+	ClassIds []string
+}
+
+// docsClass is the documentation of a provisioner of the resource type.
+type docsClass struct {
+	Class         string
+	Uri           string
+	Description   string
+	Selectors     []string
+	ResourceTypes []string
+	Params        [][]string
+	Outputs       []string
+	SecretOutputs []string
+	Example       string
+}
+
+func generateProvisionersDocs(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	sd, ok, err := state.LoadStateDirectory(".")
+	if err != nil {
+		return fmt.Errorf("failed to load existing state directory: %w", err)
+	} else if !ok {
+		return fmt.Errorf("no state directory found, run 'score-radius init' first")
+	}
+	allProvisioners, err := loader.LoadProvisionersFromDirectory(sd.Path, loader.ProvisionersFileSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to load resources provisioners in %s: %w", sd.Path, err)
+	}
+	allProvisioners = append(allProvisioners, builtInProvisioners()...)
+
+	var extension string
+	var indexTemplate, typeTemplate interface {
+		Execute(io.Writer, any) error
+	}
+	switch format, _ := cmd.Flags().GetString(provisionersDocsFormatFlag); format {
+	case "markdown":
+		extension = ".md"
+		indexTemplate = template.Must(template.New("index").Funcs(template.FuncMap{"cell": markdownCell}).Parse(markdownIndexTemplate))
+		typeTemplate = template.Must(template.New("type").Funcs(template.FuncMap{"cell": markdownCell}).Parse(markdownTypeTemplate))
+	case "html":
+		extension = ".html"
+		indexTemplate = htmltemplate.Must(htmltemplate.New("index").Parse(htmlIndexTemplate))
+		typeTemplate = htmltemplate.Must(htmltemplate.New("type").Parse(htmlTypeTemplate))
+	default:
+		return fmt.Errorf("unsupported format '%s', expected one of: markdown, html", format)
+	}
+
+	types := buildDocsTypes(allProvisioners, extension)
+	outputDir, _ := cmd.Flags().GetString(provisionersDocsOutputFlag)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := writeDocsPage(filepath.Join(outputDir, "index"+extension), indexTemplate, types); err != nil {
+		return err
+	}
+	for _, t := range types {
+		if err := writeDocsPage(filepath.Join(outputDir, t.Page), typeTemplate, t); err != nil {
+			return err
+		}
+	}
+	slog.Info(fmt.Sprintf("Wrote the documentation of %d resource types to %s", len(types), outputDir))
+	return nil
+}
+
+// buildDocsTypes groups the provisioners by resource type, sorted by type and by class.
+func buildDocsTypes(allProvisioners []provisioners.Provisioner, extension string) []docsType {
+	byType := make(map[string]*docsType)
+	out := make([]*docsType, 0)
+	for _, provisioner := range allProvisioners {
+		t, ok := byType[provisioner.ResType]
+		if !ok {
+			t = &docsType{Type: provisioner.ResType}
+			byType[provisioner.ResType] = t
+			out = append(out, t)
+		}
+		t.Classes = append(t.Classes, buildDocsClass(provisioner))
+		if t.Summary == "" {
+			t.Summary = provisioner.Description
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Type < out[j].Type
+	})
+	// the pages are named in type order, so that the same types always get the same pages
+	pages := map[string]bool{"index": true}
+	types := make([]docsType, 0, len(out))
+	for _, t := range out {
+		t.Page = uniqueDocsPageName(pages, docsPageName(t.Type)) + extension
+		sort.SliceStable(t.Classes, func(i, j int) bool {
+			return t.Classes[i].Class < t.Classes[j].Class
+		})
+		for _, c := range t.Classes {
+			t.ClassIds = append(t.ClassIds, c.Class)
+		}
+		types = append(types, *t)
+	}
+	return types
+}
+
+func buildDocsClass(provisioner provisioners.Provisioner) docsClass {
+	out := docsClass{
+		Class:         provisioner.Class,
+		Uri:           provisioner.Uri,
+		Description:   provisioner.Description,
+		ResourceTypes: provisioner.ResourceTypes(),
+		Outputs:       provisioner.Outputs,
+		SecretOutputs: provisioner.SecretOutputs,
+	}
+	if provisioner.ResId != "" {
+		out.Selectors = append(out.Selectors, fmt.Sprintf("id %s", provisioner.ResId))
+	}
+	if len(provisioner.Workloads) > 0 {
+		out.Selectors = append(out.Selectors, fmt.Sprintf("workloads %s", strings.Join(provisioner.Workloads, ", ")))
+	}
+	if len(provisioner.MetadataSelector) > 0 {
+		out.Selectors = append(out.Selectors, fmt.Sprintf("metadata %v", provisioner.MetadataSelector))
+	}
+
+	schemas := provisioner.EffectiveParams()
+	exampleParams := make(map[string]interface{})
+	for _, name := range schemas.Names() {
+		schema := schemas[name]
+		var defaultValue, allowedValues string
+		if schema.Default != nil {
+			defaultValue = fmt.Sprint(schema.Default)
+		}
+		if len(schema.Enum) > 0 {
+			allowedValues = strings.Trim(fmt.Sprint(schema.Enum), "[]")
+		}
+		required := ""
+		if schema.Required {
+			required = "yes"
+			exampleParams[name] = exampleParamValue(name, schema)
+		}
+		out.Params = append(out.Params, []string{name, schema.Type, required, defaultValue, allowedValues, schema.Description})
+	}
+
+	example := docsExampleResource{Type: provisioner.ResType, Id: provisioner.ResId, Params: exampleParams}
+	if provisioner.Class != provisioners.AnyClass {
+		example.Class = provisioner.Class
+	}
+	if len(provisioner.MetadataSelector) > 0 {
+		example.Metadata = provisioner.MetadataSelector
+	}
+	buff := new(bytes.Buffer)
+	enc := yaml.NewEncoder(buff)
+	enc.SetIndent(2)
+	_ = enc.Encode(map[string]interface{}{"resources": map[string]docsExampleResource{docsPageName(provisioner.ResType): example}})
+	out.Example = strings.TrimSpace(buff.String())
+	return out
+}
+
+// docsExampleResource is the Score resource of the example, with its fields in the usual order.
+type docsExampleResource struct {
+	Type     string                 `yaml:"type"`
+	Class    string                 `yaml:"class,omitempty"`
+	Id       string                 `yaml:"id,omitempty"`
+	Metadata map[string]interface{} `yaml:"metadata,omitempty"`
+	Params   map[string]interface{} `yaml:"params,omitempty"`
+}
+
+// exampleParamValue returns the value of a required param in the example resource.
+func exampleParamValue(name string, schema provisioners.ParamSchema) interface{} {
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+	switch schema.Type {
+	case "number", "integer":
+		return 0
+	case "boolean":
+		return false
+	case "array":
+		return []interface{}{}
+	case "object":
+		return map[string]interface{}{}
+	}
+	return fmt.Sprintf("<%s>", name)
+}
+
+var docsPageNamePattern = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// docsPageName returns the file name of the page of a resource type, without its extension.
+func docsPageName(resType string) string {
+	return docsPageNamePattern.ReplaceAllString(resType, "-")
+}
+
+// uniqueDocsPageName returns the page name, or the page name with the first free numeric suffix, such that it is not
+// taken by the index or another type, ignoring the case for case-insensitive file systems. It is then marked as taken.
+func uniqueDocsPageName(taken map[string]bool, base string) string {
+	name := base
+	for i := 2; taken[strings.ToLower(name)]; i++ {
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+	taken[strings.ToLower(name)] = true
+	return name
+}
+
+func writeDocsPage(path string, tmpl interface {
+	Execute(io.Writer, any) error
+}, data any) error {
+	buff := new(bytes.Buffer)
+	if err := tmpl.Execute(buff, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, buff.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// markdownCell escapes a value for a Markdown table cell.
+func markdownCell(value string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(value, "|", "\\|"), "\n", " ")
+}
+
+const markdownIndexTemplate = `# Resource types
+
+| Type | Classes | Description |
+| ---- | ------- | ----------- |
+{{- range . }}
+| [{{ .Type }}]({{ .Page }}) | {{ range $i, $c := .ClassIds }}{{ if $i }}, {{ end }}` + "`{{ $c }}`" + `{{ end }} | {{ cell .Summary }} |
+{{- end }}
+`
+
+const markdownTypeTemplate = `# {{ .Type }}
+{{ range .Classes }}
+## Class ` + "`{{ .Class }}`" + `
+{{ if .Description }}
+{{ .Description }}
+{{ end }}
+- Provisioner: ` + "`{{ .Uri }}`" + `
+{{- if .Selectors }}
+- Only for: {{ range $i, $s := .Selectors }}{{ if $i }}; {{ end }}{{ $s }}{{ end }}
+{{- end }}
+{{- if .ResourceTypes }}
+- Radius resources: {{ range $i, $r := .ResourceTypes }}{{ if $i }}, {{ end }}` + "`{{ $r }}`" + `{{ end }}
+{{- end }}
+- Outputs: {{ if .Outputs }}{{ range $i, $o := .Outputs }}{{ if $i }}, {{ end }}` + "`{{ $o }}`" + `{{ end }}{{ else }}none{{ end }}
+{{- if .SecretOutputs }}
+- Secret outputs: {{ range $i, $o := .SecretOutputs }}{{ if $i }}, {{ end }}` + "`{{ $o }}`" + `{{ end }}
+{{- end }}
+{{ if .Params }}
+| Param | Type | Required | Default | Allowed | Description |
+| ----- | ---- | -------- | ------- | ------- | ----------- |
+{{- range .Params }}
+| {{ range $i, $v := . }}{{ if $i }} | {{ end }}{{ cell $v }}{{ end }} |
+{{- end }}
+{{ else }}
+Any params are accepted.
+{{ end }}
+` + "```yaml" + `
+{{ .Example }}
+` + "```" + `
+{{ end -}}
+`
+
+const htmlIndexTemplate = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Resource types</title></head>
+<body>
+<h1>Resource types</h1>
+<table>
+<tr><th>Type</th><th>Classes</th><th>Description</th></tr>
+{{- range . }}
+<tr><td><a href="{{ .Page }}">{{ .Type }}</a></td><td>{{ range $i, $c := .ClassIds }}{{ if $i }}, {{ end }}<code>{{ $c }}</code>{{ end }}</td><td>{{ .Summary }}</td></tr>
+{{- end }}
+</table>
+</body>
+</html>
+`
+
+const htmlTypeTemplate = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{ .Type }}</title></head>
+<body>
+<h1>{{ .Type }}</h1>
+{{- range .Classes }}
+<h2>Class <code>{{ .Class }}</code></h2>
+{{- if .Description }}
+<p>{{ .Description }}</p>
+{{- end }}
+<ul>
+<li>Provisioner: <code>{{ .Uri }}</code></li>
+{{- if .Selectors }}
+<li>Only for: {{ range $i, $s := .Selectors }}{{ if $i }}; {{ end }}{{ $s }}{{ end }}</li>
+{{- end }}
+{{- if .ResourceTypes }}
+<li>Radius resources: {{ range $i, $r := .ResourceTypes }}{{ if $i }}, {{ end }}<code>{{ $r }}</code>{{ end }}</li>
+{{- end }}
+<li>Outputs: {{ if .Outputs }}{{ range $i, $o := .Outputs }}{{ if $i }}, {{ end }}<code>{{ $o }}</code>{{ end }}{{ else }}none{{ end }}</li>
+{{- if .SecretOutputs }}
+<li>Secret outputs: {{ range $i, $o := .SecretOutputs }}{{ if $i }}, {{ end }}<code>{{ $o }}</code>{{ end }}</li>
+{{- end }}
+</ul>
+{{- if .Params }}
+<table>
+<tr><th>Param</th><th>Type</th><th>Required</th><th>Default</th><th>Allowed</th><th>Description</th></tr>
+{{- range .Params }}
+<tr>{{ range . }}<td>{{ . }}</td>{{ end }}</tr>
+{{- end }}
+</table>
+{{- else }}
+<p>Any params are accepted.</p>
+{{- end }}
+<pre><code>{{ .Example }}</code></pre>
+{{- end }}
+</body>
+</html>
+`
+
+func init() {
+	provisionersDocs.Flags().StringP(provisionersDocsOutputFlag, "o", "provisioners-docs", "The directory of the generated pages")
+	provisionersDocs.Flags().StringP(provisionersDocsFormatFlag, "f", "markdown", "Format of the pages: markdown (default), html")
+	provisionersGroup.AddCommand(provisionersDocs)
+}
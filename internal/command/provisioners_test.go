@@ -0,0 +1,351 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/score-spec/score-radius/internal/provisioners/loader"
+	"github.com/score-spec/score-radius/internal/state"
+)
+
+func TestProvisionersDescribe(t *testing.T) {
+	_ = changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	stdout, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "describe", "route"})
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Uri: template://default-provisioners/route\n")
+	assert.Contains(t, stdout, "| port  | integer | true     |         |         | The port of the workload the requests are routed to |\n")
+
+	stdout, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "describe", "volume", "--class", "memory", "--format", "json"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{
+  "Uri": "template://default-provisioners/volume-memory",
+  "Type": "volume",
+  "Class": "memory",
+  "Description": "Provides an ephemeral volume stored in memory",
+  "Params": null,
+  "Outputs": ["kind", "managedStore"],
+  "SecretOutputs": null
+}]`, stdout)
+
+	stdout, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "describe", "environment"})
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Uri: environment://default\n")
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "describe", "unknown"})
+	assert.EqualError(t, err, "no provisioner found for resource type 'unknown'")
+}
+
+func TestProvisionersAddUpdateRemove(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample", "--no-default-provisioners"})
+	require.NoError(t, err)
+
+	sourcePath := filepath.Join(td, "things.yaml")
+	assert.NoError(t, os.WriteFile(sourcePath, []byte(`
+- uri: template://thing
+  type: thing
+  class: default
+  description: first
+`), 0644))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "add", sourcePath})
+	require.NoError(t, err)
+
+	lock, err := loader.LoadLock(filepath.Join(td, state.DefaultRelativeStateDirectory))
+	require.NoError(t, err)
+	require.Len(t, lock.Sources, 1)
+	assert.Equal(t, sourcePath, lock.Sources[0].Uri)
+	require.Len(t, lock.Sources[0].Files, 1)
+	fileName := lock.Sources[0].Files[0].Name
+
+	stdout, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "list", "--format", "json"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{
+  "Type": "environment",
+  "Class": "*",
+  "Params": ["secure"],
+  "Outputs": null,
+  "Description": "Reads the outputs from the params of the generated Bicep file, set by the .bicepparam file",
+  "File": ""
+}, {
+  "Type": "thing",
+  "Class": "default",
+  "Params": null,
+  "Outputs": null,
+  "Description": "first",
+  "File": "`+fileName+`",
+  "Source": "`+sourcePath+`"
+}]`, stdout)
+
+	assert.NoError(t, os.WriteFile(sourcePath, []byte(`
+- uri: template://thing
+  type: thing
+  class: default
+  description: second
+`), 0644))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "update"})
+	require.NoError(t, err)
+	stdout, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "list", "--format", "json"})
+	require.NoError(t, err)
+	assert.Contains(t, stdout, `"Description": "second"`)
+	assert.NotContains(t, stdout, `"Description": "first"`)
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "remove", sourcePath})
+	require.NoError(t, err)
+	entries, err := os.ReadDir(filepath.Join(td, state.DefaultRelativeStateDirectory))
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Name(), loader.ProvisionersFileSuffix)
+	}
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "remove", sourcePath})
+	assert.EqualError(t, err, "no provisioners installed from '"+sourcePath+"'")
+}
+
+func TestProvisionersRemove_from_legacy_sources(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample", "--no-default-provisioners"})
+	require.NoError(t, err)
+
+	sourcePath := filepath.Join(td, "things.yaml")
+	assert.NoError(t, os.WriteFile(sourcePath, []byte(`
+- uri: template://thing
+  type: thing
+  class: default
+`), 0644))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "add", sourcePath})
+	require.NoError(t, err)
+	stateDir := filepath.Join(td, state.DefaultRelativeStateDirectory)
+	lock, err := loader.LoadLock(stateDir)
+	require.NoError(t, err)
+	require.Len(t, lock.Sources, 1)
+	fileName := lock.Sources[0].Files[0].Name
+
+	// replace the lock with the sources manifest written by earlier versions
+	assert.NoError(t, os.Remove(filepath.Join(stateDir, loader.LockFileName)))
+	assert.NoError(t, os.WriteFile(filepath.Join(stateDir, "provisioners.sources.yaml"), []byte(`sources:
+  - uri: `+sourcePath+`
+    files:
+      - `+fileName+`
+`), 0644))
+
+	lock, err = loader.LoadLock(stateDir)
+	require.NoError(t, err)
+	require.Len(t, lock.Sources, 1)
+	assert.Equal(t, sourcePath, lock.Sources[0].Uri)
+	require.Len(t, lock.Sources[0].Files, 1)
+	assert.Equal(t, fileName, lock.Sources[0].Files[0].Name)
+	assert.Len(t, lock.Sources[0].Files[0].Sha256, 64)
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "remove", sourcePath})
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(stateDir, fileName))
+	assert.NoFileExists(t, filepath.Join(stateDir, "provisioners.sources.yaml"))
+	assert.FileExists(t, filepath.Join(stateDir, loader.LockFileName))
+}
+
+func TestProvisionersTest(t *testing.T) {
+	td := changeToTempDir(t)
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "thing.provisioners.yaml"), []byte(`
+- uri: template://thing
+  type: thing
+  class: default
+  params: [size]
+  init: |
+    name: {{ splitList "." .Id | last }}
+  state: |
+    generation: {{ add1 (dig "generation" 0 .State) }}
+  outputs: |
+    name: {{ .Init.name }}
+    size: {{ .Params.size }}
+  manifests: |
+    resource {{ .SymbolicName }} 'Applications.Core/extenders@2023-10-01-preview' = {
+      name: {{ bicepLiteralString .Init.name }}
+    }
+`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "resource.yaml"), []byte(`
+type: thing
+params:
+  size: 2
+`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "state.yaml"), []byte(`generation: 3`), 0644))
+
+	stdout, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{
+		"provisioners", "test", "--resource", "resource.yaml", "--state", "state.yaml", "--provisioners", "thing.provisioners.yaml", "--name", "db",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `provisioner: template://thing
+match: type 'thing', class 'default'
+uid: thing.default#example.db
+params:
+    size: 2
+state:
+    generation: 4
+shared: {}
+outputs:
+    name: db
+    size: 2
+manifests: |-
+    resource db 'Applications.Core/extenders@2023-10-01-preview' = {
+      name: 'db'
+    }
+`, stdout)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "broken.provisioners.yaml"), []byte(`
+- uri: template://thing
+  type: thing
+  class: default
+  outputs: |
+    name: thing
+    size: {{ fail "size is not supported" }}
+`), 0644))
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"provisioners", "test", "--resource", "resource.yaml", "--provisioners", "broken.provisioners.yaml",
+	})
+	assert.EqualError(t, err, `failed to provision resource: outputs template failed: failed to execute template: template: outputs:2:9: executing "outputs" at <fail "size is not supported">: error calling fail: size is not supported
+  template line 2: size: {{ fail "size is not supported" }}`)
+}
+
+func TestProvisionersValidate(t *testing.T) {
+	td := changeToTempDir(t)
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "a.provisioners.yaml"), []byte(`
+- uri: template://a/thing
+  type: thing
+  class: default
+  params: [size, color]
+  outputs: |
+    size: {{ .Params.size }}
+- uri: template://a/thing-again
+  type: thing
+  class: default
+`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "b.provisioners.yaml"), []byte(`
+- uri: template://b/thing
+  type: thing
+  class: default
+`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "bb.provisioners.yaml"), []byte(`
+- uri: template://bb/other
+  type: other
+  class: default
+  format: yaml
+`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "c.provisioners.yaml"), []byte(`
+- uri: template://c/thing
+  type: thing
+  class: large
+  manifests: |
+    {{ nosuchfunc }}
+`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(td, "d.provisioners.yaml"), []byte(`
+- uri: template://d/thing
+  type: thing
+  class: medium
+`), 0644))
+
+	stdout, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{
+		"provisioners", "validate", "--format", "json", "a.provisioners.yaml", "b.provisioners.yaml", "bb.provisioners.yaml", "c.provisioners.yaml",
+	})
+	assert.EqualError(t, err, "found 3 errors and 2 warnings in the provisioners files")
+	assert.JSONEq(t, `[
+  {"file": "bb.provisioners.yaml", "severity": "error", "message": "provisioner 'template://bb/other': unsupported format 'yaml', expected one of: bicep"},
+  {"file": "c.provisioners.yaml", "severity": "error", "message": "provisioner 'template://c/thing': manifests template: template: manifests:1: function \"nosuchfunc\" not defined\n  template line 1: {{ nosuchfunc }}"},
+  {"file": "a.provisioners.yaml", "uri": "template://a/thing", "severity": "warning", "message": "param 'color' is declared but not used by any template"},
+  {"file": "a.provisioners.yaml", "uri": "template://a/thing-again", "severity": "error", "message": "never used, 'template://a/thing' in the same file matches the same resources"},
+  {"file": "b.provisioners.yaml", "uri": "template://b/thing", "severity": "warning", "message": "shadowed by 'template://a/thing' in a.provisioners.yaml which matches the same resources"}
+]`, stdout)
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+		"provisioners", "validate", "d.provisioners.yaml",
+	})
+	assert.NoError(t, err)
+
+	for _, scheme := range []string{"extender", "environment"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(td, "e.provisioners.yaml"), []byte(`
+- uri: `+scheme+`://e/thing
+  type: thing
+`), 0644))
+		stdout, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{
+			"provisioners", "validate", "--format", "json", "e.provisioners.yaml",
+		})
+		assert.Error(t, err)
+		assert.Contains(t, stdout, "provisioner '"+scheme+"://e/thing': the "+scheme+":// provisioners are built in and can't be declared in a provisioners file")
+	}
+}
+
+func TestProvisionersDocs(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample"})
+	require.NoError(t, err)
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "docs", "--output", "site"})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "site", "index.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "| [volume](volume.md) | `azure-keyvault`, `default`, `memory` | Provides an ephemeral volume stored on the node disk |\n")
+	assert.Contains(t, string(raw), "| [environment](environment.md) | `*` |")
+	raw, err = os.ReadFile(filepath.Join(td, "site", "route.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "- Radius resources: `Applications.Core/gateways`\n")
+	assert.Contains(t, string(raw), "| port | integer | yes |  |  | The port of the workload the requests are routed to |\n")
+	assert.Contains(t, string(raw), "```yaml\nresources:\n  route:\n    type: route\n    class: default\n    params:\n      host: <host>\n      port: 0\n```\n")
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "docs", "--output", "site", "--format", "html"})
+	require.NoError(t, err)
+	raw, err = os.ReadFile(filepath.Join(td, "site", "route.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "<li>Radius resources: <code>Applications.Core/gateways</code></li>\n")
+	assert.Contains(t, string(raw), "      host: &lt;host&gt;\n")
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "docs", "--format", "pdf"})
+	assert.EqualError(t, err, "unsupported format 'pdf', expected one of: markdown, html")
+}
+
+func TestProvisionersDocs_with_colliding_page_names(t *testing.T) {
+	td := changeToTempDir(t)
+	_, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"init", "--no-sample", "--no-default-provisioners"})
+	require.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(td, state.DefaultRelativeStateDirectory, "custom.provisioners.yaml"), []byte(`
+- uri: template://custom/index
+  type: index
+- uri: template://custom/a-slash-b
+  type: a/b
+- uri: template://custom/a-dash-b
+  type: a-b
+`), 0644))
+
+	_, _, err = executeAndResetCommand(context.Background(), rootCmd, []string{"provisioners", "docs", "--output", "site"})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(td, "site", "index.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "| [a-b](a-b.md) |")
+	assert.Contains(t, string(raw), "| [a/b](a-b-2.md) |")
+	assert.Contains(t, string(raw), "| [index](index-2.md) |")
+	for page, resType := range map[string]string{"a-b.md": "a-b", "a-b-2.md": "a/b", "index-2.md": "index"} {
+		raw, err = os.ReadFile(filepath.Join(td, "site", page))
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(raw), "# "+resType+"\n"), page)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/score-spec/score-go/formatter"
+	scoretypes "github.com/score-spec/score-go/types"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/score-spec/score-radius/internal/provisioners"
+	"github.com/score-spec/score-radius/internal/provisioners/loader"
+	"github.com/score-spec/score-radius/internal/state"
+)
+
+const (
+	provisionersTestResourceFlag     = "resource"
+	provisionersTestStateFlag        = "state"
+	provisionersTestProvisionersFlag = "provisioners"
+	provisionersTestWorkloadFlag     = "workload"
+	provisionersTestNameFlag         = "name"
+)
+
+var provisionersTest = &cobra.Command{
+	Use:   "test --resource FILE [--provisioners FILE] [--state FILE] [--format yaml|json]",
+	Short: "Render the provisioner of a sample resource",
+	Long: `The test command provisions a single sample resource, like 'generate' does, and prints the chosen provisioner,
+the params, the new state, the outputs, and the manifests. The resource file is a Score resource with its type, class,
+id, metadata, and params. The provisioners are loaded from the given file, or from the state directory.
+`,
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	RunE:          testProvisioner,
+}
+
+// provisionerTestResult is the output of the test command.
+type provisionerTestResult struct {
+	Provisioner string                 `yaml:"provisioner" json:"provisioner"`
+	Match       string                 `yaml:"match" json:"match"`
+	Uid         string                 `yaml:"uid" json:"uid"`
+	Params      map[string]interface{} `yaml:"params" json:"params"`
+	State       map[string]interface{} `yaml:"state" json:"state"`
+	Shared      map[string]interface{} `yaml:"shared" json:"shared"`
+	Outputs     map[string]interface{} `yaml:"outputs" json:"outputs"`
+	Manifests   string                 `yaml:"manifests" json:"manifests"`
+}
+
+func testProvisioner(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	resourcePath, _ := cmd.Flags().GetString(provisionersTestResourceFlag)
+	var resource scoretypes.Resource
+	if err := decodeYamlFile(resourcePath, &resource); err != nil {
+		return fmt.Errorf("failed to load resource: %w", err)
+	} else if resource.Type == "" {
+		return fmt.Errorf("failed to load resource: %s: type not set", resourcePath)
+	}
+
+	var priorState map[string]interface{}
+	if v, _ := cmd.Flags().GetString(provisionersTestStateFlag); v != "" {
+		if err := decodeYamlFile(v, &priorState); err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+	}
+
+	var availableProvisioners []provisioners.Provisioner
+	if v, _ := cmd.Flags().GetString(provisionersTestProvisionersFlag); v != "" {
+		raw, err := os.ReadFile(v)
+		if err != nil {
+			return fmt.Errorf("failed to read provisioners: %w", err)
+		}
+		if availableProvisioners, err = loader.LoadProvisioners(raw); err != nil {
+			return fmt.Errorf("failed to load provisioners: %s: %w", v, err)
+		}
+	} else {
+		sd, ok, err := state.LoadStateDirectory(".")
+		if err != nil {
+			return fmt.Errorf("failed to load existing state directory: %w", err)
+		} else if !ok {
+			return fmt.Errorf("no state directory found, run 'score-radius init' first or use --%s", provisionersTestProvisionersFlag)
+		}
+		if availableProvisioners, err = loader.LoadProvisionersFromDirectory(sd.Path, loader.ProvisionersFileSuffix); err != nil {
+			return fmt.Errorf("failed to load provisioners: %w", err)
+		}
+	}
+
+	availableProvisioners = append(availableProvisioners, builtInProvisioners()...)
+
+	workloadName, _ := cmd.Flags().GetString(provisionersTestWorkloadFlag)
+	resourceName, _ := cmd.Flags().GetString(provisionersTestNameFlag)
+	workload := scoretypes.Workload{
+		ApiVersion: "score.dev/v1b1",
+		Metadata:   scoretypes.WorkloadMetadata{"name": workloadName},
+		Containers: scoretypes.WorkloadContainers{"main": scoretypes.Container{Image: "."}},
+		Resources:  scoretypes.WorkloadResources{resourceName: resource},
+	}
+	currentState := &state.State{SharedState: make(map[string]interface{})}
+	currentState, err := currentState.WithWorkload(&workload, nil, state.WorkloadExtras{})
+	if err != nil {
+		return fmt.Errorf("failed to add the sample workload: %w", err)
+	}
+	if currentState, err = currentState.WithPrimedResources(); err != nil {
+		return fmt.Errorf("failed to prime resource: %w", err)
+	}
+	resUid := slices.Collect(maps.Keys(currentState.Resources))[0]
+	if priorState != nil {
+		resState := currentState.Resources[resUid]
+		resState.State = priorState
+		currentState.Resources[resUid] = resState
+	}
+	currentState = state.WithSymbolicNames(currentState)
+
+	manifests, currentState, err := provisioners.ProvisionResources(cmd.Context(), currentState, availableProvisioners)
+	if err != nil {
+		return fmt.Errorf("failed to provision resource: %w", err)
+	}
+	resState := currentState.Resources[resUid]
+	result := provisionerTestResult{
+		Provisioner: resState.ProvisionerUri,
+		Match:       resState.Extras.ProvisionerMatch,
+		Uid:         string(resUid),
+		Params:      resState.Params,
+		State:       resState.State,
+		Shared:      currentState.SharedState,
+		Outputs:     resState.Outputs,
+		Manifests:   strings.TrimSpace(manifests),
+	}
+
+	var outputFormatter formatter.OutputFormatter
+	switch cmd.Flag("format").Value.String() {
+	case "json":
+		outputFormatter = &formatter.JSONOutputFormatter[provisionerTestResult]{Data: result, Out: cmd.OutOrStdout()}
+	default:
+		outputFormatter = &formatter.YAMLOutputFormatter[provisionerTestResult]{Data: result, Out: cmd.OutOrStdout()}
+	}
+	return outputFormatter.Display()
+}
+
+// decodeYamlFile decodes the yaml file into out, rejecting unknown fields.
+func decodeYamlFile(path string, out interface{}) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	if err := dec.Decode(out); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func init() {
+	provisionersTest.Flags().StringP(provisionersTestResourceFlag, "r", "", "The yaml file of the sample Score resource")
+	provisionersTest.Flags().String(provisionersTestStateFlag, "", "An optional yaml file of the prior state of the resource")
+	provisionersTest.Flags().String(provisionersTestProvisionersFlag, "", "An optional provisioners file to use instead of the state directory")
+	provisionersTest.Flags().String(provisionersTestWorkloadFlag, "example", "The name of the workload declaring the resource")
+	provisionersTest.Flags().String(provisionersTestNameFlag, "sample", "The name of the resource in the workload")
+	provisionersTest.Flags().StringP("format", "f", "yaml", "Format of the output: yaml (default), json")
+	_ = provisionersTest.MarkFlagRequired(provisionersTestResourceFlag)
+	provisionersGroup.AddCommand(provisionersTest)
+}
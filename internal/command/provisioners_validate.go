@@ -0,0 +1,111 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/score-spec/score-go/formatter"
+	"github.com/spf13/cobra"
+
+	"github.com/score-spec/score-radius/internal/provisioners/loader"
+	"github.com/score-spec/score-radius/internal/state"
+)
+
+const provisionersValidateStrictFlag = "strict"
+
+var provisionersValidate = &cobra.Command{
+	Use:   "validate [FILE...] [--format table|json] [--strict]",
+	Short: "Check the provisioners files",
+	Long: `The validate command loads the provisioners files in the given order, or the provisioners files of the state
+directory, and reports their issues. The files which fail to load, with an unsupported format or a template which
+doesn't parse, and the provisioners never used since a previous one in the same file matches the same resources are
+errors. The provisioners shadowed by a previous file and the declared params which no template reads are warnings.
+The command fails when there are errors, or warnings with --strict.
+`,
+	Args:          cobra.ArbitraryArgs,
+	SilenceErrors: true,
+	RunE:          validateProvisioners,
+}
+
+func validateProvisioners(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	paths := args
+	if len(paths) == 0 {
+		sd, ok, err := state.LoadStateDirectory(".")
+		if err != nil {
+			return fmt.Errorf("failed to load existing state directory: %w", err)
+		} else if !ok {
+			return fmt.Errorf("no state directory found, run 'score-radius init' first or pass the files to validate")
+		}
+		items, err := os.ReadDir(sd.Path)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if !item.IsDir() && strings.HasSuffix(item.Name(), loader.ProvisionersFileSuffix) {
+				paths = append(paths, filepath.Join(sd.Path, item.Name()))
+			}
+		}
+	}
+
+	issues := loader.ValidateFiles(paths)
+	var errorCount, warningCount int
+	for _, issue := range issues {
+		if issue.Severity == loader.SeverityError {
+			errorCount++
+		} else {
+			warningCount++
+		}
+	}
+
+	var outputFormatter formatter.OutputFormatter
+	switch cmd.Flag("format").Value.String() {
+	case "json":
+		outputFormatter = &formatter.JSONOutputFormatter[[]loader.Issue]{Data: issues, Out: cmd.OutOrStdout()}
+	default:
+		if len(issues) == 0 {
+			slog.Info(fmt.Sprintf("No issues found in %d provisioners files", len(paths)))
+			return nil
+		}
+		rows := make([][]string, 0, len(issues))
+		for _, issue := range issues {
+			rows = append(rows, []string{issue.File, issue.Uri, issue.Severity, issue.Message})
+		}
+		outputFormatter = &formatter.TableOutputFormatter{
+			Headers: []string{"File", "Provisioner", "Severity", "Message"},
+			Rows:    rows,
+			Out:     cmd.OutOrStdout(),
+		}
+	}
+	if err := outputFormatter.Display(); err != nil {
+		return err
+	}
+
+	if strict, _ := cmd.Flags().GetBool(provisionersValidateStrictFlag); errorCount > 0 || (strict && warningCount > 0) {
+		return fmt.Errorf("found %d errors and %d warnings in the provisioners files", errorCount, warningCount)
+	}
+	return nil
+}
+
+func init() {
+	provisionersValidate.Flags().StringP("format", "f", "table", "Format of the output: table (default), json")
+	provisionersValidate.Flags().Bool(provisionersValidateStrictFlag, false, "Fail on warnings too")
+	provisionersGroup.AddCommand(provisionersValidate)
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+
+	"github.com/score-spec/score-radius/internal/schema"
+)
+
+var (
+	schemaGroup = &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON schemas of the score-radius files",
+	}
+	schemaProvisioners = &cobra.Command{
+		Use:   "provisioners",
+		Short: "Print the JSON schema of the provisioners files",
+		Long: `The provisioners command prints the JSON schema of the *.provisioners.yaml files, for editors to validate and
+complete them.
+`,
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return printSchema(cmd, schema.Provisioners())
+		},
+	}
+	schemaWorkload = &cobra.Command{
+		Use:   "workload",
+		Short: "Print the JSON schema of the score-radius extensions of the Score workloads",
+		Long: `The workload command prints the JSON schema of the workload annotations understood by score-radius. It is
+meant to be combined with the Score workload schema.
+`,
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return printSchema(cmd, schema.WorkloadExtensions())
+		},
+	}
+)
+
+func printSchema(cmd *cobra.Command, out schema.Schema) error {
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func init() {
+	schemaGroup.AddCommand(schemaProvisioners)
+	schemaGroup.AddCommand(schemaWorkload)
+	rootCmd.AddCommand(schemaGroup)
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/score-spec/score-radius/internal/provisioners"
+	"github.com/score-spec/score-radius/internal/provisioners/defaults"
+)
+
+func TestSchemaProvisioners(t *testing.T) {
+	stdout, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"schema", "provisioners"})
+	require.NoError(t, err)
+	var out struct {
+		Schema string `json:"$schema"`
+		Type   string `json:"type"`
+		Items  struct {
+			Required             []string                          `json:"required"`
+			AdditionalProperties bool                              `json:"additionalProperties"`
+			Properties           map[string]map[string]interface{} `json:"properties"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &out))
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", out.Schema)
+	assert.Equal(t, "array", out.Type)
+	assert.Equal(t, []string{"uri", "type"}, out.Items.Required)
+	assert.False(t, out.Items.AdditionalProperties)
+	assert.Equal(t, map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Restricts the provisioner to the resources declared by one of these workloads."}, out.Items.Properties["workloads"])
+	assert.Contains(t, out.Items.Properties["params"], "oneOf")
+	assert.NotContains(t, out.Items.Properties, "SourceFile")
+	for _, scheme := range provisioners.Schemes {
+		assert.Contains(t, out.Items.Properties["uri"]["description"], scheme[0]+"://")
+	}
+
+	// the fields of the default provisioners are all in the schema
+	var defaultProvisioners []map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(defaults.Provisioners, &defaultProvisioners))
+	for _, p := range defaultProvisioners {
+		for key := range p {
+			assert.Contains(t, out.Items.Properties, key)
+		}
+	}
+}
+
+func TestSchemaWorkload(t *testing.T) {
+	stdout, _, err := executeAndResetCommand(context.Background(), rootCmd, []string{"schema", "workload"})
+	require.NoError(t, err)
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &out))
+	annotations := out["properties"].(map[string]interface{})["metadata"].(map[string]interface{})["properties"].(map[string]interface{})["annotations"].(map[string]interface{})
+	assert.Contains(t, annotations["properties"], "radius.score.dev/main-container")
+}
@@ -17,44 +17,108 @@ package convert
 import (
 	"bytes"
 	"fmt"
-	"html/template"
+	"log/slog"
 	"maps"
-	"os"
-	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/score-spec/score-go/framework"
 	scoretypes "github.com/score-spec/score-go/types"
 
+	"github.com/score-spec/score-radius/internal/bicep"
 	"github.com/score-spec/score-radius/internal/state"
 )
 
+// MainContainerAnnotation is the workload annotation used to select which container becomes the
+// Applications.Core/containers container when the workload has more than one container.
+const MainContainerAnnotation = "radius.score.dev/main-container"
+
+// Annotations are the workload annotations understood by the converter, with their description.
+var Annotations = map[string]string{
+	MainContainerAnnotation: "The name of the container which becomes the Applications.Core/containers container, otherwise the one named like the workload, otherwise the first one in alphabetical order.",
+}
+
 type Data struct {
 	WorkloadName string
-	Spec         scoretypes.Workload
+	// SymbolicName is the Bicep symbolic name of the workload, its secret store is named with the
+	// state.SecretStoreSuffix.
+	SymbolicName string
+	// Spec is the workload with its container variables substituted and written as Bicep strings.
+	Spec scoretypes.Workload
+	// MainContainer is the name of the container mapped to the Applications.Core/containers container.
+	MainContainer string
+	// Sidecars are the other containers of the workload, added to the pod through the runtimes.kubernetes.pod patch.
+	Sidecars []Sidecar
+	// MainContainerMounts are the volume mounts patched onto the main container.
+	MainContainerMounts []VolumeMount
+	// MainContainerResources are the compute resources patched onto the main container, nil if not set.
+	MainContainerResources *ContainerResources
+	// FilesConfigMap holds the non-secret container files, nil if there are none.
+	FilesConfigMap *FilesSource
+	// SecretStore holds the container files and variables referencing secret outputs, nil if there are none.
+	SecretStore *SecretStore
+	// SecretVariables is the secret store key of each secret variable, by container and variable name.
+	SecretVariables map[string]map[string]string
+	// Volumes are the ephemeral and persistent volumes of the main container in Radius.
+	Volumes []RadiusVolume
+	// PodVolumes are the ephemeral volumes added to the pod, shared with sidecars or mounted with a sub path or read-only,
+	// their mounts are part of the container mounts.
+	PodVolumes []PodVolume
+	// Connections are the resources connected to the container by Score resource name, which names the connection.
+	// This excludes the resources used as volumes and the resources without a Radius resource.
+	Connections map[string]Connection
+}
+
+// Connection is a resource connected to the main container.
+type Connection struct {
+	// SymbolicName is the Bicep symbolic name of the resource.
+	SymbolicName string
+	// Source is the source of the connection when it is not the id of the resource, like the url of another container.
+	Source   string
+	Resource scoretypes.Resource
+}
+
+type Sidecar struct {
+	Name      string
+	Container scoretypes.Container
+	Mounts    []VolumeMount
+	Resources *ContainerResources
 }
 
+// Header returns the Bicep declarations that must be written once at the top of the generated file, before any of
+// the workloads returned by Workload and the resources manifests.
+func Header() string {
+	return radiusHeaderTemplate
+}
+
+// Workload returns the Bicep body of the given workload. It does not include the declarations returned by Header.
 func Workload(currentState *state.State, workloadName string) (string, error) {
-	resOutputs, err := currentState.GetResourceOutputForWorkload(workloadName)
+	substitute, err := buildSubstitutionFunction(currentState, workloadName)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate outputs: %w", err)
+		return "", err
 	}
-	sf := framework.BuildSubstitutionFunction(currentState.Workloads[workloadName].Spec.Metadata, resOutputs)
 
 	spec := currentState.Workloads[workloadName].Spec
 	containers := maps.Clone(spec.Containers)
+	containerFiles := make(map[string]map[string]containerFile, len(containers))
+	containerSecretVariables := make(map[string][]string, len(containers))
 	for containerName, container := range containers {
-		if container.Variables, err = convertContainerVariables(container.Variables, sf); err != nil {
+		if container.Variables, containerSecretVariables[containerName], err = convertContainerVariables(container.Variables, substitute); err != nil {
 			return "", fmt.Errorf("workload: %s: container: %s: variables: %w", workloadName, containerName, err)
 		}
 
-		if container.Files, err = convertContainerFiles(container.Files, currentState.Workloads[workloadName].File, sf); err != nil {
+		if containerFiles[containerName], err = convertContainerFiles(container.Files, currentState.Workloads[workloadName].File, substitute); err != nil {
 			return "", fmt.Errorf("workload: %s: container: %s: files: %w", workloadName, containerName, err)
 		}
 		containers[containerName] = container
 	}
 	spec.Containers = containers
 	resources := maps.Clone(spec.Resources)
+	resSymbolicNames := make(map[string]string, len(resources))
+	noConnections := make(map[string]bool)
+	connectionSources := make(map[string]string)
 	for resName, res := range resources {
 		resUid := framework.NewResourceUid(workloadName, resName, res.Type, res.Class, res.Id)
 		resState, ok := currentState.Resources[resUid]
@@ -65,13 +129,84 @@ func Workload(currentState *state.State, workloadName string) (string, error) {
 		res.Id = &resState.Id
 		res.Type = resState.Type
 		resources[resName] = res
+		resSymbolicNames[resName] = resState.Extras.SymbolicName
+		noConnections[resName] = resState.Extras.NoConnection
+		connectionSources[resName] = resState.Extras.ConnectionSource
 	}
 	spec.Resources = resources
 
+	// resources used as volumes are mounted rather than connected
+	volumeMounts := volumeMountCounts(spec)
+	connections := make(map[string]Connection, len(resources))
+	for resName, res := range resources {
+		if volumeMounts[resName] == 0 && !noConnections[resName] {
+			connections[resName] = Connection{SymbolicName: resSymbolicNames[resName], Source: connectionSources[resName], Resource: res}
+		}
+	}
+
+	mainContainer, err := selectMainContainer(spec)
+	if err != nil {
+		return "", fmt.Errorf("workload: %s: %w", workloadName, err)
+	}
+	filesConfigMap := &FilesSource{Name: workloadName + "-files", Volume: filesVolumeName}
+	secretStore := &SecretStore{Name: workloadName + "-secrets", Volume: secretFilesVolumeName}
+	secretVariables := make(map[string]map[string]string)
+	volumes := newWorkloadVolumes(spec)
+	var mainContainerMounts []VolumeMount
+	var mainContainerResources *ContainerResources
+	sidecars := make([]Sidecar, 0, len(spec.Containers)-1)
+	for _, containerName := range slices.Sorted(maps.Keys(spec.Containers)) {
+		mounts, err := buildFileMounts(containerName, containerFiles[containerName], filesConfigMap, secretStore)
+		if err != nil {
+			return "", fmt.Errorf("workload: %s: container: %s: files: %w", workloadName, containerName, err)
+		}
+		if secrets := containerSecretVariables[containerName]; len(secrets) > 0 {
+			secretVariables[containerName] = moveSecretVariables(containerName, spec.Containers[containerName].Variables, secrets, secretStore)
+		}
+		volumeMounts, err := volumes.addContainerVolumes(currentState, workloadName, spec.Containers[containerName].Volumes, containerName == mainContainer)
+		if err != nil {
+			return "", fmt.Errorf("workload: %s: container: %s: volumes: %w", workloadName, containerName, err)
+		}
+		mounts = append(mounts, volumeMounts...)
+		containerResources, err := convertContainerResources(spec.Containers[containerName].Resources)
+		if err != nil {
+			return "", fmt.Errorf("workload: %s: container: %s: resources: %w", workloadName, containerName, err)
+		}
+		if containerName == mainContainer {
+			mainContainerMounts = mounts
+			mainContainerResources = containerResources
+		} else {
+			sidecars = append(sidecars, Sidecar{Name: containerName, Container: spec.Containers[containerName], Mounts: mounts, Resources: containerResources})
+		}
+	}
+	if len(sidecars) > 0 {
+		sidecarNames := make([]string, 0, len(sidecars))
+		for _, sidecar := range sidecars {
+			sidecarNames = append(sidecarNames, sidecar.Name)
+		}
+		slog.Info(fmt.Sprintf("Workload '%s' has multiple containers: '%s' is the main container, '%s' are added as sidecars through the runtimes.kubernetes.pod patch", workloadName, mainContainer, strings.Join(sidecarNames, "', '")))
+	}
+
 	// Convert the Score workload to a Radius manifest
 	data := Data{
-		WorkloadName: workloadName,
-		Spec:         spec,
+		WorkloadName:  workloadName,
+		SymbolicName:  currentState.Workloads[workloadName].Extras.SymbolicName,
+		Spec:          spec,
+		MainContainer: mainContainer,
+		Sidecars:      sidecars,
+
+		MainContainerMounts:    mainContainerMounts,
+		MainContainerResources: mainContainerResources,
+		Volumes:                volumes.Radius,
+		PodVolumes:             volumes.Pod,
+		Connections:            connections,
+	}
+	if len(filesConfigMap.Items) > 0 {
+		data.FilesConfigMap = filesConfigMap
+	}
+	if len(secretStore.Files) > 0 || len(secretStore.Variables) > 0 {
+		data.SecretStore = secretStore
+		data.SecretVariables = secretVariables
 	}
 	radiusManifest, err := convertToRadius(data)
 	if err != nil {
@@ -81,6 +216,31 @@ func Workload(currentState *state.State, workloadName string) (string, error) {
 	return radiusManifest, nil
 }
 
+// selectMainContainer returns the container which is mapped to the Applications.Core/containers container. This is
+// the container named by the MainContainerAnnotation if set, the only container, the container named like the workload,
+// or the first container in alphabetical order.
+func selectMainContainer(spec scoretypes.Workload) (string, error) {
+	if annotations, ok := spec.Metadata["annotations"].(map[string]interface{}); ok {
+		if v, ok := annotations[MainContainerAnnotation]; ok {
+			name, _ := v.(string)
+			if _, ok := spec.Containers[name]; !ok {
+				return "", fmt.Errorf("annotation '%s': container '%v' does not exist", MainContainerAnnotation, v)
+			}
+			return name, nil
+		}
+	}
+	if name, _ := spec.Metadata["name"].(string); name != "" {
+		if _, ok := spec.Containers[name]; ok {
+			return name, nil
+		}
+	}
+	names := slices.Sorted(maps.Keys(spec.Containers))
+	if len(names) == 0 {
+		return "", fmt.Errorf("no containers")
+	}
+	return names[0], nil
+}
+
 // convertToRadius converts a Score workload to a Radius manifest
 func convertToRadius(data Data) (string, error) {
 	fileContent, err := generateRadiusContainers(data)
@@ -92,7 +252,7 @@ func convertToRadius(data Data) (string, error) {
 }
 
 func generateRadiusContainers(data Data) (string, error) {
-	t, err := template.New("").Funcs(sprig.FuncMap()).Parse(radiusContainersTemplate)
+	t, err := template.New("").Funcs(sprig.TxtFuncMap()).Funcs(bicep.FuncMap()).Parse(radiusContainersTemplate)
 	if err != nil {
 		return "", err
 	}
@@ -104,51 +264,3 @@ func generateRadiusContainers(data Data) (string, error) {
 
 	return buf.String(), nil
 }
-
-func convertContainerVariables(input scoretypes.ContainerVariables, sf func(string) (string, error)) (map[string]string, error) {
-	outMap := make(map[string]string, len(input))
-	for key, value := range input {
-		out, err := framework.SubstituteString(value, sf)
-		if err != nil {
-			return nil, fmt.Errorf("%s: %w", key, err)
-		}
-		outMap[key] = out
-	}
-	return outMap, nil
-}
-
-func convertContainerFiles(input map[string]scoretypes.ContainerFile, scoreFile *string, sf func(string) (string, error)) (map[string]scoretypes.ContainerFile, error) {
-	output := make(map[string]scoretypes.ContainerFile, len(input))
-	for target, file := range input {
-		var content string
-		if file.Content != nil {
-			content = *file.Content
-		} else if file.Source != nil {
-			sourcePath := *file.Source
-			if !filepath.IsAbs(sourcePath) && scoreFile != nil {
-				sourcePath = filepath.Join(filepath.Dir(*scoreFile), sourcePath)
-			}
-			if rawContent, err := os.ReadFile(sourcePath); err != nil {
-				return nil, fmt.Errorf("%s: source: failed to read file '%s': %w", target, sourcePath, err)
-			} else {
-				content = string(rawContent)
-			}
-		} else {
-			return nil, fmt.Errorf("%s: missing 'content' or 'source'", target)
-		}
-
-		var err error
-		if file.NoExpand == nil || !*file.NoExpand {
-			content, err = framework.SubstituteString(string(content), sf)
-			if err != nil {
-				return nil, fmt.Errorf("%s: failed to substitute in content: %w", target, err)
-			}
-		}
-		file.Source = nil
-		file.Content = &content
-		bTrue := true
-		file.NoExpand = &bTrue
-		output[target] = file
-	}
-	return output, nil
-}
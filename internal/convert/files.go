@@ -0,0 +1,146 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	scoretypes "github.com/score-spec/score-go/types"
+
+	"github.com/score-spec/score-radius/internal/bicep"
+)
+
+const filesVolumeName = "score-files"
+
+// FilesSource is the set of non-secret container files of a workload, stored in a Kubernetes ConfigMap written to the
+// runtimes.kubernetes.base manifest and mounted in the pod through a single volume.
+type FilesSource struct {
+	// Name is the name of the ConfigMap.
+	Name string
+	// Volume is the name of the pod volume.
+	Volume string
+	Items  []FileItem
+}
+
+// FileItem is the content of a single container file or secret variable, stored under Key in its FilesSource or
+// SecretStore.
+type FileItem struct {
+	Key string
+	// Value is the content as a Bicep string.
+	Value string
+	// Binary indicates that the content is standard base64 encoded.
+	Binary bool
+	// Mode is the optional file access mode.
+	Mode *int64
+}
+
+// VolumeMount is a volume mounted in a container of the pod.
+type VolumeMount struct {
+	Volume    string
+	MountPath string
+	SubPath   string
+	ReadOnly  bool
+}
+
+// containerFile is a Score container file after its source has been read and its content substituted.
+type containerFile struct {
+	scoretypes.ContainerFile
+	// Value is the content as a Bicep string.
+	Value string
+	// Secret indicates that the content references at least one secret resource output.
+	Secret bool
+}
+
+func convertContainerFiles(input map[string]scoretypes.ContainerFile, scoreFile *string, substitute substitutionFunc) (map[string]containerFile, error) {
+	output := make(map[string]containerFile, len(input))
+	for target, file := range input {
+		if file.BinaryContent != nil {
+			file.Source = nil
+			output[target] = containerFile{ContainerFile: file, Value: bicep.LiteralString(*file.BinaryContent)}
+			continue
+		}
+
+		var content string
+		if file.Content != nil {
+			content = *file.Content
+		} else if file.Source != nil {
+			sourcePath := *file.Source
+			if !filepath.IsAbs(sourcePath) && scoreFile != nil {
+				sourcePath = filepath.Join(filepath.Dir(*scoreFile), sourcePath)
+			}
+			if rawContent, err := os.ReadFile(sourcePath); err != nil {
+				return nil, fmt.Errorf("%s: source: failed to read file '%s': %w", target, sourcePath, err)
+			} else {
+				content = string(rawContent)
+			}
+		} else {
+			return nil, fmt.Errorf("%s: missing 'content', 'binaryContent', or 'source'", target)
+		}
+
+		value := bicep.LiteralString(content)
+		secret := false
+		if file.NoExpand == nil || !*file.NoExpand {
+			var err error
+			if value, secret, err = substitute(content); err != nil {
+				return nil, fmt.Errorf("%s: failed to substitute in content: %w", target, err)
+			}
+		}
+		file.Source = nil
+		file.Content = &content
+		output[target] = containerFile{ContainerFile: file, Value: value, Secret: secret}
+	}
+	return output, nil
+}
+
+// buildFileMounts adds the files of a container to the given ConfigMap or secret store and returns the volume
+// mounts for the container. Each file is mounted on its own through a sub path of the volume.
+func buildFileMounts(containerName string, files map[string]containerFile, configMap *FilesSource, secretStore *SecretStore) ([]VolumeMount, error) {
+	mounts := make([]VolumeMount, 0, len(files))
+	for i, target := range slices.Sorted(maps.Keys(files)) {
+		file := files[target]
+		item := FileItem{
+			Key:    fmt.Sprintf("%s-%d", containerName, i),
+			Value:  file.Value,
+			Binary: file.BinaryContent != nil,
+		}
+		if file.Mode != nil {
+			mode, err := strconv.ParseInt(*file.Mode, 8, 32)
+			if err != nil || mode < 0 || mode > 0777 {
+				return nil, fmt.Errorf("%s: mode: '%s' is not a valid octal file mode", target, *file.Mode)
+			}
+			item.Mode = &mode
+		}
+
+		volume := configMap.Volume
+		if file.Secret {
+			secretStore.Files = append(secretStore.Files, item)
+			volume = secretStore.Volume
+		} else {
+			configMap.Items = append(configMap.Items, item)
+		}
+		mounts = append(mounts, VolumeMount{
+			Volume:    volume,
+			MountPath: target,
+			SubPath:   item.Key,
+			ReadOnly:  true,
+		})
+	}
+	return mounts, nil
+}
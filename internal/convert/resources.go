@@ -0,0 +1,150 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"maps"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+
+	scoretypes "github.com/score-spec/score-go/types"
+)
+
+var (
+	cpuQuantityRegex    = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?|\.[0-9]+)(m?)$`)
+	memoryQuantityRegex = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?|\.[0-9]+)(Ki|Mi|Gi|Ti|Pi|Ei|k|M|G|T|P|E)?$`)
+
+	memoryUnits = map[string]*big.Rat{
+		"":   big.NewRat(1, 1),
+		"k":  new(big.Rat).SetInt64(1e3),
+		"M":  new(big.Rat).SetInt64(1e6),
+		"G":  new(big.Rat).SetInt64(1e9),
+		"T":  new(big.Rat).SetInt64(1e12),
+		"P":  new(big.Rat).SetInt64(1e15),
+		"E":  new(big.Rat).SetInt64(1e18),
+		"Ki": new(big.Rat).SetInt64(1 << 10),
+		"Mi": new(big.Rat).SetInt64(1 << 20),
+		"Gi": new(big.Rat).SetInt64(1 << 30),
+		"Ti": new(big.Rat).SetInt64(1 << 40),
+		"Pi": new(big.Rat).SetInt64(1 << 50),
+		"Ei": new(big.Rat).SetInt64(1 << 60),
+	}
+)
+
+// ContainerResources are the compute resources of a container, validated and converted to Kubernetes quantities.
+type ContainerResources struct {
+	Limits   map[string]string
+	Requests map[string]string
+}
+
+// ValidateWorkload checks the parts of a workload which are converted by score-radius beyond the Score schema, so
+// that an invalid Score file is reported before any resource is provisioned.
+func ValidateWorkload(spec *scoretypes.Workload) error {
+	for _, containerName := range slices.Sorted(maps.Keys(spec.Containers)) {
+		if _, err := convertContainerResources(spec.Containers[containerName].Resources); err != nil {
+			return fmt.Errorf("containers: %s: resources: %w", containerName, err)
+		}
+	}
+	return nil
+}
+
+// convertContainerResources validates the cpu and memory requests and limits of a container and returns them in the
+// Kubernetes quantity format. Cpu is converted to whole cpus or millicpus. It returns nil if no resources are set.
+func convertContainerResources(input *scoretypes.ContainerResources) (*ContainerResources, error) {
+	if input == nil || (input.Limits == nil && input.Requests == nil) {
+		return nil, nil
+	}
+	out := &ContainerResources{}
+	var limitCpu, limitMemory, requestCpu, requestMemory *big.Rat
+	var err error
+	if input.Limits != nil {
+		if out.Limits, limitCpu, limitMemory, err = convertResourcesLimits(input.Limits); err != nil {
+			return nil, fmt.Errorf("limits: %w", err)
+		}
+	}
+	if input.Requests != nil {
+		if out.Requests, requestCpu, requestMemory, err = convertResourcesLimits(input.Requests); err != nil {
+			return nil, fmt.Errorf("requests: %w", err)
+		}
+	}
+	if limitCpu != nil && requestCpu != nil && requestCpu.Cmp(limitCpu) > 0 {
+		return nil, fmt.Errorf("requests: cpu: '%s' is greater than the limit '%s'", *input.Requests.Cpu, *input.Limits.Cpu)
+	}
+	if limitMemory != nil && requestMemory != nil && requestMemory.Cmp(limitMemory) > 0 {
+		return nil, fmt.Errorf("requests: memory: '%s' is greater than the limit '%s'", *input.Requests.Memory, *input.Limits.Memory)
+	}
+	return out, nil
+}
+
+func convertResourcesLimits(input *scoretypes.ResourcesLimits) (map[string]string, *big.Rat, *big.Rat, error) {
+	out := make(map[string]string, 2)
+	var cpu, memory *big.Rat
+	if input.Cpu != nil {
+		millis, err := parseCpuQuantity(*input.Cpu)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("cpu: %w", err)
+		}
+		if millis%1000 == 0 {
+			out["cpu"] = strconv.FormatInt(millis/1000, 10)
+		} else {
+			out["cpu"] = strconv.FormatInt(millis, 10) + "m"
+		}
+		cpu = new(big.Rat).SetInt64(millis)
+	}
+	if input.Memory != nil {
+		bytes, err := parseMemoryQuantity(*input.Memory)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("memory: %w", err)
+		}
+		out["memory"] = *input.Memory
+		memory = bytes
+	}
+	return out, cpu, memory, nil
+}
+
+// parseCpuQuantity returns the number of millicpus of a cpu quantity like 2, 0.5, or 125m.
+func parseCpuQuantity(raw string) (int64, error) {
+	match := cpuQuantityRegex.FindStringSubmatch(raw)
+	if match == nil {
+		return 0, fmt.Errorf("'%s' is not a valid quantity, expected whole or fractional cpus, or millicpus with the 'm' suffix", raw)
+	}
+	value, _ := new(big.Rat).SetString(match[1])
+	if match[2] == "" {
+		value.Mul(value, big.NewRat(1000, 1))
+	}
+	if !value.IsInt() {
+		return 0, fmt.Errorf("'%s' is more precise than 1m", raw)
+	} else if !value.Num().IsInt64() {
+		return 0, fmt.Errorf("'%s' is too large", raw)
+	}
+	return value.Num().Int64(), nil
+}
+
+// parseMemoryQuantity returns the number of bytes of a memory quantity like 128974848, 129M, or 123Mi.
+func parseMemoryQuantity(raw string) (*big.Rat, error) {
+	match := memoryQuantityRegex.FindStringSubmatch(raw)
+	if match == nil {
+		return nil, fmt.Errorf("'%s' is not a valid quantity, expected bytes with an optional k, M, G, T, P, E, Ki, Mi, Gi, Ti, Pi, or Ei suffix", raw)
+	}
+	value, _ := new(big.Rat).SetString(match[1])
+	value.Mul(value, memoryUnits[match[2]])
+	if !value.IsInt() {
+		return nil, fmt.Errorf("'%s' is not a whole number of bytes", raw)
+	}
+	return value, nil
+}
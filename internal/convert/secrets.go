@@ -0,0 +1,146 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/score-spec/score-go/framework"
+	scoretypes "github.com/score-spec/score-go/types"
+
+	"github.com/score-spec/score-radius/internal/bicep"
+	"github.com/score-spec/score-radius/internal/state"
+)
+
+const secretFilesVolumeName = "score-secret-files"
+
+var secretKeyInvalidCharsRegex = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// SecretStore is the Applications.Core/secretStores resource holding the container files and variables which
+// reference secret resource outputs, so that they are never written in plain container definitions.
+type SecretStore struct {
+	// Name is the name of the secret store and of the Kubernetes secret created by Radius.
+	Name string
+	// Volume is the name of the pod volume mounting the Files.
+	Volume string
+	Files  []FileItem
+	// Variables are referenced by the secret environment variables of the containers.
+	Variables []FileItem
+}
+
+// substitutionFunc substitutes the placeholders in a string and returns it as a Bicep string, it also reports whether
+// any of the placeholders resolved to a secret.
+type substitutionFunc func(string) (string, bool, error)
+
+// placeholderRegex matches the ${...} placeholders and the $$ escapes like the Score substitution does.
+var placeholderRegex = regexp.MustCompile(`\$((?:\$?{([^}]*)})|\$)`)
+
+// isSecretValue returns whether a resolved placeholder value references a secret, resource outputs reading secrets
+// are expressed with the Bicep listSecrets() function.
+func isSecretValue(value string) bool {
+	return strings.Contains(value, ".listSecrets()")
+}
+
+// buildSubstitutionFunction returns the substitution function for the workload. A placeholder is secret when it
+// references an output declared as secret by the provisioner of the resource, or when it resolves to a value read
+// through listSecrets(). Only the ${...} expressions of the resolved values are kept as Bicep interpolations, the
+// rest of the string, including escaped $${...} placeholders, is written literally.
+func buildSubstitutionFunction(currentState *state.State, workloadName string) (substitutionFunc, error) {
+	resOutputs, err := currentState.GetResourceOutputForWorkload(workloadName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate outputs: %w", err)
+	}
+	spec := currentState.Workloads[workloadName].Spec
+	sf := framework.BuildSubstitutionFunction(spec.Metadata, resOutputs)
+
+	secretOutputs := make(map[string][]string, len(spec.Resources))
+	for resName, res := range spec.Resources {
+		resUid := framework.NewResourceUid(workloadName, resName, res.Type, res.Class, res.Id)
+		secretOutputs[resName] = currentState.Resources[resUid].Extras.SecretOutputs
+	}
+
+	return func(src string) (string, bool, error) {
+		secret := false
+		parts := make([]bicep.StringPart, 0, 1)
+		last := 0
+		for _, match := range placeholderRegex.FindAllStringSubmatchIndex(src, -1) {
+			parts = append(parts, bicep.StringPart{Text: src[last:match[0]]})
+			last = match[1]
+			if src[match[2]] == '$' {
+				// $$ and $${...} are escapes for a literal $ and ${...}
+				parts = append(parts, bicep.StringPart{Text: src[match[0]+1 : match[1]]})
+				continue
+			}
+			ref := src[match[4]:match[5]]
+			value, err := sf(ref)
+			if err != nil {
+				return "", false, err
+			}
+			parts = append(parts, bicep.StringPart{Text: value, Interpolate: true})
+			refParts := framework.SplitRefParts(ref)
+			if len(refParts) > 2 && refParts[0] == "resources" && slices.Contains(secretOutputs[refParts[1]], refParts[2]) {
+				secret = true
+			}
+			secret = secret || isSecretValue(value)
+		}
+		parts = append(parts, bicep.StringPart{Text: src[last:]})
+		return bicep.JoinString(parts...), secret, nil
+	}, nil
+}
+
+// convertContainerVariables substitutes the placeholders in the variables, as Bicep strings, and returns the names of the variables
+// which reference secrets.
+func convertContainerVariables(input scoretypes.ContainerVariables, substitute substitutionFunc) (map[string]string, []string, error) {
+	outMap := make(map[string]string, len(input))
+	secrets := make([]string, 0)
+	for key, value := range input {
+		out, secret, err := substitute(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", key, err)
+		}
+		outMap[key] = out
+		if secret {
+			secrets = append(secrets, key)
+		}
+	}
+	slices.Sort(secrets)
+	return outMap, secrets, nil
+}
+
+// hasKey returns whether a file or a variable of the secret store has the key.
+func (s *SecretStore) hasKey(key string) bool {
+	return slices.ContainsFunc(s.Files, func(item FileItem) bool { return item.Key == key }) ||
+		slices.ContainsFunc(s.Variables, func(item FileItem) bool { return item.Key == key })
+}
+
+// moveSecretVariables moves the secret variables of a container to the secret store and returns the secret store key
+// of each of them. The invalid characters of the names are replaced, so a key already taken by another name gets a
+// numeric suffix.
+func moveSecretVariables(containerName string, variables map[string]string, secrets []string, secretStore *SecretStore) map[string]string {
+	keys := make(map[string]string, len(secrets))
+	for _, name := range secrets {
+		base := fmt.Sprintf("%s.env.%s", containerName, secretKeyInvalidCharsRegex.ReplaceAllString(name, "-"))
+		key := base
+		for i := 2; secretStore.hasKey(key); i++ {
+			key = fmt.Sprintf("%s-%d", base, i)
+		}
+		secretStore.Variables = append(secretStore.Variables, FileItem{Key: key, Value: variables[name]})
+		keys[name] = key
+	}
+	return keys
+}
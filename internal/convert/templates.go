@@ -14,7 +14,9 @@
 
 package convert
 
-const radiusContainersTemplate = `{{ $workloadName := .WorkloadName }}{{ $firstWorkload := true }}{{ $service := .Spec.Service }}{{ $resources := .Spec.Resources }}
+// radiusHeaderTemplate holds the declarations shared by all the workloads and resources of a Bicep file, it must only
+// be written once per file.
+const radiusHeaderTemplate = `
 extension radius
 
 @description('The Radius Application ID. Injected automatically by the rad CLI.')
@@ -22,45 +24,59 @@ param application string
 
 @description('The Radius Environment ID. Injected automatically by the rad CLI.')
 param environment string
-{{ range $containerName, $container := .Spec.Containers }}
-{{- if $firstWorkload }}
-resource {{ $workloadName }} 'Applications.Core/containers@2023-10-01-preview' = {
-  name: '{{ $workloadName }}'
+`
+
+const radiusContainersTemplate = `{{ $workloadName := .WorkloadName }}{{ $container := index .Spec.Containers .MainContainer }}{{ $sidecars := .Sidecars }}{{ $service := .Spec.Service }}{{ $resources := .Connections }}{{ $symbolicName := .SymbolicName }}{{ if gt (len $sidecars) 0 }}
+// Workload '{{ $workloadName }}' has multiple containers: '{{ .MainContainer }}' is the main container,
+// {{ range $i, $sidecar := $sidecars }}{{ if $i }}, {{ end }}'{{ $sidecar.Name }}'{{ end }} added as sidecars through the runtimes.kubernetes.pod patch.
+{{- end }}
+resource {{ $symbolicName }} 'Applications.Core/containers@2023-10-01-preview' = {
+  name: {{ bicepLiteralString $workloadName }}
   properties: {
     application: application
     environment: environment
     container: {
-      image: '{{ $container.Image }}'
+      image: {{ bicepLiteralString $container.Image }}
 
       {{- if (gt (len $container.Command) 0) }}
       command: [
         {{- range $i, $cmd := $container.Command }}
-        '{{ $cmd }}'
+        {{ bicepLiteralString $cmd }}
         {{- end }}
       ]{{- end }}
 
       {{- if (gt (len $container.Args) 0) }}
       args: [
         {{- range $i, $arg := $container.Args }}
-        '{{ $arg }}'
+        {{ bicepLiteralString $arg }}
         {{- end }}
       ]{{- end }}
 
       {{- if (gt (len $container.Variables) 0) }}
+      {{- $secretKeys := index .SecretVariables .MainContainer }}
       env: {
         {{- range $variableName, $variableValue := $container.Variables }}
-        {{ $variableName }}: {
-          value: '{{ $variableValue }}'
+        {{ bicepKey $variableName }}: {
+          {{- with index $secretKeys $variableName }}
+          valueFrom: {
+            secretRef: {
+              source: {{ $symbolicName }}_secrets.id
+              key: {{ bicepLiteralString . }}
+            }
+          }
+          {{- else }}
+          value: {{ $variableValue }}
+          {{- end }}
         }{{- end }}
       }{{- end }}
 
       {{- if and (ne $service nil) (gt (len $service.Ports) 0) }}
       ports: {
         {{- range $portName, $port := $service.Ports }}
-        '{{ $portName }}': {
+        {{ bicepLiteralString $portName }}: {
           port: {{ $port.Port }}
           {{- if ne $port.Protocol nil }}
-          protocol: '{{ $port.Protocol }}'
+          protocol: {{ bicepLiteralString $port.Protocol }}
           {{- end }}
           {{- if ne $port.TargetPort nil }}
           containerPort: {{ $port.TargetPort }}
@@ -72,12 +88,12 @@ resource {{ $workloadName }} 'Applications.Core/containers@2023-10-01-preview' =
       livenessProbe: {
         {{- if (ne $container.LivenessProbe.Exec nil) }}
         kind: 'exec'
-        command: {{ $container.LivenessProbe.Exec.Command }}
+        command: {{ bicepLiteralString (join " " $container.LivenessProbe.Exec.Command) }}
         {{- else if (ne $container.LivenessProbe.HttpGet nil) }}
         kind: 'httpGet'
         containerPort: {{ $container.LivenessProbe.HttpGet.Port }}
         {{- if (ne $container.LivenessProbe.HttpGet.Path "") }}
-        path: '{{ $container.LivenessProbe.HttpGet.Path }}'
+        path: {{ bicepLiteralString $container.LivenessProbe.HttpGet.Path }}
         {{- end }}
         {{- end }}
       }{{- end }}
@@ -85,28 +101,264 @@ resource {{ $workloadName }} 'Applications.Core/containers@2023-10-01-preview' =
       readinessProbe: {
        {{- if (ne $container.ReadinessProbe.Exec nil) }}
         kind: 'exec'
-        command: {{ $container.ReadinessProbe.Exec.Command }}
+        command: {{ bicepLiteralString (join " " $container.ReadinessProbe.Exec.Command) }}
         {{- else if (ne $container.ReadinessProbe.HttpGet nil) }}
         kind: 'httpGet'
         containerPort: {{ $container.ReadinessProbe.HttpGet.Port }}
         {{- if (ne $container.ReadinessProbe.HttpGet.Path "") }}
-        path: '{{ $container.ReadinessProbe.HttpGet.Path }}'
+        path: {{ bicepLiteralString $container.ReadinessProbe.HttpGet.Path }}
+        {{- end }}
         {{- end }}
+      }{{- end }}
+
+      {{- if .Volumes }}
+      volumes: {
+        {{- range $volume := .Volumes }}
+        {{ bicepLiteralString $volume.Name }}: {
+          kind: {{ bicepLiteralString $volume.Kind }}
+          {{- if $volume.ManagedStore }}
+          managedStore: {{ bicepLiteralString $volume.ManagedStore }}
+          {{- else }}
+          source: {{ bicepString $volume.Source }}
+          {{- end }}
+          mountPath: {{ bicepLiteralString $volume.MountPath }}
+          {{- if $volume.ReadOnly }}
+          permission: 'read'
+          {{- end }}
+        }
         {{- end }}
       }{{- end }}
     }
 
     {{- if gt (len $resources) 0 }}
     connections: {
-      {{- range $resource := $resources }}
-      {{- $resourceId := splitList "." $resource.Id | last }}
-      {{ $resourceId }}: {
-        source: {{ $resourceId }}.id
-        disableDefaultEnvVars: {{ and (ne $resource.Params.disableDefaultEnvVars nil) $resource.Params.disableDefaultEnvVars }}
+      {{- range $name, $connection := $resources }}
+      {{ bicepKey $name }}: {
+        source: {{ if $connection.Source }}{{ bicepString $connection.Source }}{{ else }}{{ $connection.SymbolicName }}.id{{ end }}
+        disableDefaultEnvVars: {{ bicepValue (default false $connection.Resource.Params.disableDefaultEnvVars) }}
       }
       {{- end }}
     }
     {{- end }}
+
+    {{- if or $sidecars .MainContainerMounts .MainContainerResources }}
+    runtimes: {
+      kubernetes: {
+        {{- with .FilesConfigMap }}
+        base: string({
+          apiVersion: 'v1'
+          kind: 'ConfigMap'
+          metadata: {
+            name: {{ bicepLiteralString .Name }}
+          }
+          {{- template "filesData" (dict "items" .Items "binary" false) }}
+          {{- template "filesData" (dict "items" .Items "binary" true) }}
+        })
+        {{- end }}
+        pod: {
+          containers: [
+            {{- if or .MainContainerMounts .MainContainerResources }}
+            {
+              name: {{ bicepLiteralString $workloadName }}
+              {{- template "containerResources" .MainContainerResources }}
+              {{- template "volumeMounts" .MainContainerMounts }}
+            }
+            {{- end }}
+            {{- range $sidecar := $sidecars }}
+            {
+              name: {{ bicepLiteralString $sidecar.Name }}
+              image: {{ bicepLiteralString $sidecar.Container.Image }}
+
+              {{- if (gt (len $sidecar.Container.Command) 0) }}
+              command: [
+                {{- range $i, $cmd := $sidecar.Container.Command }}
+                {{ bicepLiteralString $cmd }}
+                {{- end }}
+              ]{{- end }}
+
+              {{- if (gt (len $sidecar.Container.Args) 0) }}
+              args: [
+                {{- range $i, $arg := $sidecar.Container.Args }}
+                {{ bicepLiteralString $arg }}
+                {{- end }}
+              ]{{- end }}
+
+              {{- if (gt (len $sidecar.Container.Variables) 0) }}
+              {{- $secretKeys := index $.SecretVariables $sidecar.Name }}
+              env: [
+                {{- range $variableName, $variableValue := $sidecar.Container.Variables }}
+                {
+                  name: {{ bicepLiteralString $variableName }}
+                  {{- with index $secretKeys $variableName }}
+                  valueFrom: {
+                    secretKeyRef: {
+                      name: {{ bicepLiteralString $.SecretStore.Name }}
+                      key: {{ bicepLiteralString . }}
+                    }
+                  }
+                  {{- else }}
+                  value: {{ $variableValue }}
+                  {{- end }}
+                }{{- end }}
+              ]{{- end }}
+
+              {{- if (ne $sidecar.Container.LivenessProbe nil) }}
+              livenessProbe: {{ template "kubernetesProbe" $sidecar.Container.LivenessProbe }}
+              {{- end }}
+              {{- if (ne $sidecar.Container.ReadinessProbe nil) }}
+              readinessProbe: {{ template "kubernetesProbe" $sidecar.Container.ReadinessProbe }}
+              {{- end }}
+              {{- template "containerResources" $sidecar.Resources }}
+              {{- template "volumeMounts" $sidecar.Mounts }}
+            }
+            {{- end }}
+          ]
+          {{- if or .FilesConfigMap (and .SecretStore .SecretStore.Files) .PodVolumes }}
+          volumes: [
+            {{- with .FilesConfigMap }}
+            {
+              name: {{ bicepLiteralString .Volume }}
+              configMap: {
+                name: {{ bicepLiteralString .Name }}
+                {{- template "volumeItems" .Items }}
+              }
+            }
+            {{- end }}
+            {{- if and .SecretStore .SecretStore.Files }}
+            {
+              name: {{ bicepLiteralString .SecretStore.Volume }}
+              secret: {
+                secretName: {{ bicepLiteralString .SecretStore.Name }}
+                {{- template "volumeItems" .SecretStore.Files }}
+              }
+            }
+            {{- end }}
+            {{- range $volume := .PodVolumes }}
+            {
+              name: {{ bicepLiteralString $volume.Name }}
+              {{- if $volume.Medium }}
+              emptyDir: {
+                medium: {{ bicepLiteralString $volume.Medium }}
+              }
+              {{- else }}
+              emptyDir: {}
+              {{- end }}
+            }
+            {{- end }}
+          ]
+          {{- end }}
+        }
+      }
+    }
+    {{- end }}
+  }
+  {{- with .SecretStore }}
+  dependsOn: [
+    {{ $symbolicName }}_secrets
+  ]
+  {{- end }}
+}
+{{- with .SecretStore }}
+
+resource {{ $symbolicName }}_secrets 'Applications.Core/secretStores@2023-10-01-preview' = {
+  name: {{ bicepLiteralString .Name }}
+  properties: {
+    application: application
+    type: 'generic'
+    data: {
+      {{- range $item := .Files }}
+      {{ bicepLiteralString $item.Key }}: {
+        value: {{ $item.Value }}
+      }
+      {{- end }}
+      {{- range $item := .Variables }}
+      {{ bicepLiteralString $item.Key }}: {
+        value: {{ $item.Value }}
+      }
+      {{- end }}
+    }
   }
-}{{ $firstWorkload := false }}{{- end }}{{ end }}
+}
+{{- end }}
+{{- define "filesData" }}
+          {{- $found := false }}
+          {{- range $item := .items }}{{ if eq $item.Binary $.binary }}
+          {{- if not $found }}{{ $found = true }}
+          {{ if $.binary }}binaryData{{ else }}data{{ end }}: {
+          {{- end }}
+            {{ bicepLiteralString $item.Key }}: {{ $item.Value }}
+          {{- end }}{{ end }}
+          {{- if $found }}
+          }
+          {{- end }}
+{{- end }}
+{{- define "volumeItems" }}
+                items: [
+                  {{- range $item := . }}
+                  {
+                    key: {{ bicepLiteralString $item.Key }}
+                    path: {{ bicepLiteralString $item.Key }}
+                    {{- if $item.Mode }}
+                    mode: {{ $item.Mode }}
+                    {{- end }}
+                  }
+                  {{- end }}
+                ]
+{{- end }}
+{{- define "containerResources" }}
+              {{- if . }}
+              resources: {
+                {{- if .Limits }}
+                limits: {
+                  {{- range $name, $quantity := .Limits }}
+                  {{ bicepKey $name }}: {{ bicepLiteralString $quantity }}
+                  {{- end }}
+                }
+                {{- end }}
+                {{- if .Requests }}
+                requests: {
+                  {{- range $name, $quantity := .Requests }}
+                  {{ bicepKey $name }}: {{ bicepLiteralString $quantity }}
+                  {{- end }}
+                }
+                {{- end }}
+              }
+              {{- end }}
+{{- end }}
+{{- define "volumeMounts" }}
+              {{- if . }}
+              volumeMounts: [
+                {{- range $mount := . }}
+                {
+                  name: {{ bicepLiteralString $mount.Volume }}
+                  mountPath: {{ bicepLiteralString $mount.MountPath }}
+                  {{- if $mount.SubPath }}
+                  subPath: {{ bicepLiteralString $mount.SubPath }}
+                  {{- end }}
+                  {{- if $mount.ReadOnly }}
+                  readOnly: true
+                  {{- end }}
+                }
+                {{- end }}
+              ]
+              {{- end }}
+{{- end }}
+{{- define "kubernetesProbe" }}{
+                {{- if (ne .Exec nil) }}
+                exec: {
+                  command: [
+                    {{- range $i, $cmd := .Exec.Command }}
+                    {{ bicepLiteralString $cmd }}
+                    {{- end }}
+                  ]
+                }
+                {{- else if (ne .HttpGet nil) }}
+                httpGet: {
+                  port: {{ .HttpGet.Port }}
+                  {{- if (ne .HttpGet.Path "") }}
+                  path: {{ bicepLiteralString .HttpGet.Path }}
+                  {{- end }}
+                }
+                {{- end }}
+              }{{- end }}
 `
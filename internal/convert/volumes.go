@@ -0,0 +1,153 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/score-spec/score-go/framework"
+	scoretypes "github.com/score-spec/score-go/types"
+
+	"github.com/score-spec/score-radius/internal/state"
+)
+
+const (
+	// VolumeKindEphemeral is the kind output of volume resources mounted as a Radius ephemeral volume or a pod emptyDir.
+	VolumeKindEphemeral = "ephemeral"
+	// VolumeKindPersistent is the kind output of volume resources backed by an Applications.Core/volumes resource.
+	VolumeKindPersistent = "persistent"
+)
+
+var volumeSourceRegex = regexp.MustCompile(`^\$\{resources\.([^.}]+)}$`)
+
+// reservedVolumeNames are the names of the pod volumes of the files, the volume resources can't be named like them.
+var reservedVolumeNames = []string{filesVolumeName, secretFilesVolumeName}
+
+// RadiusVolume is a volume mounted in the main container through the Radius container volumes.
+type RadiusVolume struct {
+	Name string
+	// Kind is either VolumeKindEphemeral or VolumeKindPersistent.
+	Kind string
+	// ManagedStore is the store of an ephemeral volume, memory or disk.
+	ManagedStore string
+	// Source is the id of the Applications.Core/volumes of a persistent volume.
+	Source    string
+	MountPath string
+	ReadOnly  bool
+}
+
+// PodVolume is an ephemeral volume added to the pod through the runtimes.kubernetes.pod patch.
+type PodVolume struct {
+	Name string
+	// Medium is the emptyDir medium, empty for the node disk.
+	Medium string
+}
+
+// workloadVolumes collects the volumes of all the containers of a workload.
+type workloadVolumes struct {
+	Radius []RadiusVolume
+	Pod    []PodVolume
+	// mountCounts is the number of mounts of each volume resource by all the containers of the workload.
+	mountCounts map[string]int
+}
+
+// newWorkloadVolumes returns the empty volumes of the workload.
+func newWorkloadVolumes(spec scoretypes.Workload) *workloadVolumes {
+	return &workloadVolumes{mountCounts: volumeMountCounts(spec)}
+}
+
+// addContainerVolumes resolves the volumes of a container against the outputs of the volume resources. An ephemeral
+// volume mounted once by the main container, without sub path and read-write, is a Radius ephemeral volume. The other
+// ephemeral volumes are returned as mounts for the pod patch, so that they can be shared with the sidecars. Persistent
+// volumes are only supported on the main container.
+func (wv *workloadVolumes) addContainerVolumes(currentState *state.State, workloadName string, volumes scoretypes.ContainerVolumes, isMain bool) ([]VolumeMount, error) {
+	spec := currentState.Workloads[workloadName].Spec
+	mounts := make([]VolumeMount, 0, len(volumes))
+	for _, target := range slices.Sorted(maps.Keys(volumes)) {
+		volume := volumes[target]
+		match := volumeSourceRegex.FindStringSubmatch(volume.Source)
+		if match == nil {
+			return nil, fmt.Errorf("%s: source: '%s' must reference a resource as ${resources.<name>}", target, volume.Source)
+		}
+		resName := match[1]
+		res, ok := spec.Resources[resName]
+		if !ok {
+			return nil, fmt.Errorf("%s: source: resource '%s' does not exist", target, resName)
+		} else if slices.Contains(reservedVolumeNames, resName) {
+			return nil, fmt.Errorf("%s: source: resource '%s' is named like a reserved pod volume, rename it", target, resName)
+		}
+		resState := currentState.Resources[framework.NewResourceUid(workloadName, resName, res.Type, res.Class, res.Id)]
+		readOnly := volume.ReadOnly != nil && *volume.ReadOnly
+
+		switch kind, _ := resState.Outputs["kind"].(string); kind {
+		case VolumeKindEphemeral:
+			managedStore, _ := resState.Outputs["managedStore"].(string)
+			if managedStore != "memory" {
+				managedStore = "disk"
+			}
+			if resState.Class == "default" && !slices.ContainsFunc(wv.Pod, func(v PodVolume) bool { return v.Name == resName }) {
+				slog.Warn(fmt.Sprintf("Workload '%s' volume '%s' of the default class is ephemeral, its data is lost when the pod is restarted", workloadName, resName))
+			}
+			if isMain && wv.mountCounts[resName] == 1 && volume.Path == nil && !readOnly {
+				wv.Radius = append(wv.Radius, RadiusVolume{Name: resName, Kind: VolumeKindEphemeral, ManagedStore: managedStore, MountPath: target})
+				continue
+			}
+			if !slices.ContainsFunc(wv.Pod, func(v PodVolume) bool { return v.Name == resName }) {
+				medium := ""
+				if managedStore == "memory" {
+					medium = "Memory"
+				}
+				wv.Pod = append(wv.Pod, PodVolume{Name: resName, Medium: medium})
+			}
+			mount := VolumeMount{Volume: resName, MountPath: target, ReadOnly: readOnly}
+			if volume.Path != nil {
+				mount.SubPath = *volume.Path
+			}
+			mounts = append(mounts, mount)
+		case VolumeKindPersistent:
+			if !isMain {
+				return nil, fmt.Errorf("%s: source: persistent volume '%s' can only be mounted in the main container", target, resName)
+			} else if volume.Path != nil {
+				return nil, fmt.Errorf("%s: path: sub paths are not supported for persistent volume '%s'", target, resName)
+			}
+			source, _ := resState.Outputs["source"].(string)
+			if source == "" {
+				return nil, fmt.Errorf("%s: source: resource '%s' has no 'source' output", target, resName)
+			}
+			wv.Radius = append(wv.Radius, RadiusVolume{Name: resName, Kind: VolumeKindPersistent, Source: source, MountPath: target, ReadOnly: readOnly})
+		default:
+			return nil, fmt.Errorf("%s: source: resource '%s' has an unsupported 'kind' output '%s', expected '%s' or '%s'", target, resName, kind, VolumeKindEphemeral, VolumeKindPersistent)
+		}
+	}
+	return mounts, nil
+}
+
+// volumeMountCounts returns the number of mounts of each resource used as a volume source by the containers of the
+// workload.
+func volumeMountCounts(spec scoretypes.Workload) map[string]int {
+	out := make(map[string]int)
+	for _, container := range spec.Containers {
+		for _, volume := range container.Volumes {
+			if match := volumeSourceRegex.FindStringSubmatch(volume.Source); match != nil {
+				out[match[1]]++
+			}
+		}
+	}
+	return out
+}
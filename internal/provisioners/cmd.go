@@ -0,0 +1,131 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provisioners
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const cmdScheme = "cmd"
+
+// CmdInput is the JSON document written to the standard input of a cmd:// provisioner.
+type CmdInput struct {
+	ResourceUid          string                    `json:"resource_uid"`
+	ResourceType         string                    `json:"resource_type"`
+	ResourceClass        string                    `json:"resource_class"`
+	ResourceId           string                    `json:"resource_id"`
+	ResourceSymbolicName string                    `json:"resource_symbolic_name"`
+	ResourceParams       map[string]interface{}    `json:"resource_params"`
+	ResourceMetadata     map[string]interface{}    `json:"resource_metadata"`
+	ResourceState        map[string]interface{}    `json:"resource_state"`
+	SharedState          map[string]interface{}    `json:"shared_state"`
+	SourceWorkload       string                    `json:"source_workload"`
+	WorkloadServices     map[string]NetworkService `json:"workload_services"`
+}
+
+// CmdOutput is the JSON document read from the standard output of a cmd:// provisioner.
+type CmdOutput struct {
+	// ResourceState is the new state of the resource, the current state is kept if it is not set.
+	ResourceState map[string]interface{} `json:"resource_state,omitempty"`
+	// SharedState is merged into the shared state, a null value removes a key.
+	SharedState     map[string]interface{} `json:"shared_state,omitempty"`
+	ResourceOutputs map[string]interface{} `json:"resource_outputs,omitempty"`
+	// Manifests are the Bicep declarations of the resource.
+	Manifests string `json:"manifests,omitempty"`
+}
+
+// provisionCmd runs the executable of a cmd:// provisioner with the resource as JSON input on its standard input, and
+// decodes its result from its standard output. Its standard error is forwarded.
+func provisionCmd(ctx context.Context, provisioner Provisioner, data Data) (*provisionResult, error) {
+	bin, err := decodeCmdBinary(provisioner.Uri)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := json.Marshal(CmdInput{
+		ResourceUid:          data.Uid,
+		ResourceType:         data.Type,
+		ResourceClass:        data.Class,
+		ResourceId:           data.Id,
+		ResourceSymbolicName: data.SymbolicName,
+		ResourceParams:       data.Params,
+		ResourceMetadata:     data.Metadata,
+		ResourceState:        data.State,
+		SharedState:          data.Shared,
+		SourceWorkload:       data.SourceWorkload,
+		WorkloadServices:     data.WorkloadServices,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cmd provisioner input: %w", err)
+	}
+
+	outputBuffer := new(bytes.Buffer)
+	cmd := exec.CommandContext(ctx, bin, provisioner.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = outputBuffer
+	cmd.Stderr = os.Stderr
+	slog.Debug(fmt.Sprintf("Executing '%s %v' for cmd provisioner", bin, provisioner.Args))
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to execute cmd provisioner '%s': %w", provisioner.Uri, err)
+	}
+
+	var output CmdOutput
+	dec := json.NewDecoder(outputBuffer)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&output); err != nil {
+		slog.Debug(fmt.Sprintf("cmd provisioner output was '%s'", outputBuffer.String()))
+		return nil, fmt.Errorf("failed to decode output from cmd provisioner '%s': %w", provisioner.Uri, err)
+	}
+	if output.ResourceOutputs == nil {
+		output.ResourceOutputs = make(map[string]interface{})
+	}
+	return &provisionResult{
+		State:       output.ResourceState,
+		SharedState: output.SharedState,
+		Outputs:     output.ResourceOutputs,
+		Manifests:   output.Manifests,
+	}, nil
+}
+
+// decodeCmdBinary returns the executable of a cmd:// uri. A path starting with ~/ is relative to the home directory,
+// a path containing a / is relative to the working directory, and a bare name is looked up in the PATH.
+func decodeCmdBinary(uri string) (string, error) {
+	bin := strings.TrimPrefix(uri, cmdScheme+"://")
+	if bin == "" {
+		return "", fmt.Errorf("cmd provisioner '%s': missing executable", uri)
+	}
+	if rest, ok := strings.CutPrefix(bin, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cmd provisioner '%s': failed to find the home directory: %w", uri, err)
+		}
+		return filepath.Join(home, rest), nil
+	} else if strings.Contains(bin, "/") {
+		return filepath.Abs(bin)
+	}
+	path, err := exec.LookPath(bin)
+	if err != nil {
+		return "", fmt.Errorf("cmd provisioner '%s': %w", uri, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provisioners
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/score-spec/score-radius/internal/bicep"
+)
+
+const environmentScheme = "environment"
+
+// environmentReservedParams are the params of the generated Bicep file which the environment values can't declare.
+var environmentReservedParams = []string{"application", "environment"}
+
+// EnvironmentParams collects the Bicep params of the generated file which the outputs of the environment resources
+// are read from, as the outputs are looked up.
+type EnvironmentParams struct {
+	// secure is whether each param is secure, by name.
+	secure map[string]bool
+	// values are the values of the params written to the .bicepparam file, they give the type of the params.
+	values map[string]interface{}
+}
+
+// NewEnvironmentParams returns an empty collection of params.
+func NewEnvironmentParams() *EnvironmentParams {
+	return &EnvironmentParams{secure: make(map[string]bool)}
+}
+
+// Provisioner returns the provisioner of the environment resources, of any class. Each output of the resources is
+// read from a string param of the generated Bicep file, named like the output and collected in the params. The
+// outputs listed by the secure param of the resource are secure params and secret outputs.
+func (e *EnvironmentParams) Provisioner() Provisioner {
+	return Provisioner{
+		Uri:         environmentScheme + "://default",
+		ResType:     "environment",
+		Class:       AnyClass,
+		Description: "Reads the outputs from the params of the generated Bicep file, set by the .bicepparam file",
+		Params: ParamsSchema{
+			"secure": {Type: "array", Description: "The names of the outputs read from secure params"},
+		},
+		NoConnection:      true,
+		environmentParams: e,
+	}
+}
+
+// Names returns the sorted names of the params.
+func (e *EnvironmentParams) Names() []string {
+	return slices.Sorted(maps.Keys(e.secure))
+}
+
+// Secure returns whether the param is secure.
+func (e *EnvironmentParams) Secure(name string) bool {
+	return e.secure[name]
+}
+
+// SetValues sets the values of the params, by name.
+func (e *EnvironmentParams) SetValues(values map[string]interface{}) {
+	e.values = values
+}
+
+// Value returns the value of the param, if it is set and not null.
+func (e *EnvironmentParams) Value(name string) (interface{}, bool) {
+	value, ok := e.values[name]
+	return value, ok && value != nil
+}
+
+// Type returns the Bicep type of the param, the type of its value when it is set and not secure, string otherwise.
+func (e *EnvironmentParams) Type(name string) string {
+	value, ok := e.Value(name)
+	if !ok || e.secure[name] {
+		return "string"
+	}
+	switch v := value.(type) {
+	case bool:
+		return "bool"
+	case int, int64, uint64:
+		return "int"
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return "int"
+		}
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return "string"
+}
+
+// Declarations returns the Bicep declarations of the params, sorted by name.
+func (e *EnvironmentParams) Declarations() string {
+	out := new(strings.Builder)
+	for _, name := range e.Names() {
+		out.WriteString("\n")
+		if e.secure[name] {
+			out.WriteString("@secure()\n")
+		}
+		_, _ = fmt.Fprintf(out, "@description(%s)\nparam %s %s\n", bicep.LiteralString(fmt.Sprintf("The %s environment value.", name)), name, e.Type(name))
+	}
+	return out.String()
+}
+
+// provisionEnvironment provisions an environment resource, its outputs are looked up as they are referenced. The
+// outputs can't be named like the given symbolic names of the workloads and resources, since a Bicep param shares
+// their namespace.
+func provisionEnvironment(provisioner Provisioner, data Data, symbolicNames map[string]bool) (*provisionResult, error) {
+	if provisioner.environmentParams == nil {
+		return nil, fmt.Errorf("provisioner '%s' is not bound to the params of the generated file", provisioner.Uri)
+	}
+	secureNames := make([]string, 0)
+	if raw, ok := data.Params["secure"].([]interface{}); ok {
+		for _, name := range raw {
+			secureNames = append(secureNames, fmt.Sprint(name))
+		}
+	}
+	return &provisionResult{
+		Outputs:       make(map[string]interface{}),
+		SecretOutputs: secureNames,
+		OutputLookup: func(keys ...string) (interface{}, error) {
+			if len(keys) != 1 {
+				return nil, fmt.Errorf("environment resource '%s': expected a single output name, got '%s'", data.Id, strings.Join(keys, "."))
+			}
+			name := keys[0]
+			if bicep.Identifier(name) != name {
+				return nil, fmt.Errorf("environment resource '%s': output '%s' is not a valid Bicep param name", data.Id, name)
+			} else if slices.Contains(environmentReservedParams, name) {
+				return nil, fmt.Errorf("environment resource '%s': output '%s' is a reserved Bicep param", data.Id, name)
+			} else if symbolicNames[name] {
+				return nil, fmt.Errorf("environment resource '%s': output '%s' is already the Bicep symbolic name of a workload or resource", data.Id, name)
+			}
+			provisioner.environmentParams.secure[name] = provisioner.environmentParams.secure[name] || slices.Contains(secureNames, name)
+			return fmt.Sprintf("${%s}", name), nil
+		},
+	}, nil
+}
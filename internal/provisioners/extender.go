@@ -0,0 +1,72 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provisioners
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/score-spec/score-radius/internal/bicep"
+)
+
+const extenderScheme = "extender"
+
+// ExtenderFallback provisions the resources which no other provisioner supports as Applications.Core/extenders, with
+// the params of the resource as properties and a recipe named like the resource type. It is only used when it is
+// added to the provisioners, see 'generate --extender-fallback'.
+var ExtenderFallback = Provisioner{
+	Uri:         extenderScheme + "://fallback",
+	Class:       AnyClass,
+	Description: "Generates an Applications.Core/extenders bicep resource for the resource types without provisioner",
+	Fallback:    true,
+}
+
+// extenderReservedProperties are the properties of the extenders which the params can't override.
+var extenderReservedProperties = []string{"application", "environment", "recipe", "resourceProvisioning", "secrets", "status"}
+
+// provisionExtender declares the Applications.Core/extenders resource of the fallback provisioner. Its outputs are not
+// known in advance, so any output is read from the properties of the extender.
+func provisionExtender(provisioner Provisioner, data Data) (*provisionResult, error) {
+	manifests := new(strings.Builder)
+	_, _ = fmt.Fprintf(manifests, "resource %s 'Applications.Core/extenders@%s' = {\n", data.SymbolicName, DefaultPortableApiVersion)
+	_, _ = fmt.Fprintf(manifests, "  name: %s\n", bicep.LiteralString(data.Name))
+	manifests.WriteString("  properties: {\n    application: application\n    environment: environment\n")
+	for _, name := range slices.Sorted(maps.Keys(data.Params)) {
+		if slices.Contains(ConnectionParams, name) {
+			continue
+		} else if slices.Contains(extenderReservedProperties, name) {
+			return nil, fmt.Errorf("param '%s' of provisioner '%s' can't be an extender property", name, provisioner.Uri)
+		}
+		value, err := bicep.Value(data.Params[name], 4)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write param '%s' of provisioner '%s': %w", name, provisioner.Uri, err)
+		}
+		_, _ = fmt.Fprintf(manifests, "    %s: %s\n", bicep.Key(name), value)
+	}
+	_, _ = fmt.Fprintf(manifests, "    recipe: {\n      name: %s\n    }\n  }\n}", bicep.LiteralString(data.Type))
+
+	return &provisionResult{
+		Outputs:   make(map[string]interface{}),
+		Manifests: manifests.String(),
+		OutputLookup: func(keys ...string) (interface{}, error) {
+			if len(keys) == 0 {
+				return nil, fmt.Errorf("at least one lookup key is required")
+			}
+			return fmt.Sprintf("${%s.properties.%s}", data.SymbolicName, strings.Join(keys, ".")), nil
+		},
+	}, nil
+}
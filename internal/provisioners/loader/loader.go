@@ -53,6 +53,9 @@ func LoadProvisionersFromDirectory(path string, filesSuffix string) ([]provision
 			if err != nil {
 				return nil, fmt.Errorf("failed to load '%s': %w", item.Name(), err)
 			}
+			for i := range p {
+				p[i].SourceFile = item.Name()
+			}
 			out = append(out, p...)
 		}
 	}
@@ -83,6 +86,8 @@ func LoadProvisioners(raw []byte) ([]provisioners.Provisioner, error) {
 			return nil, fmt.Errorf("missing uri schema '%s'", u)
 		} else if provisioner.ResType == "" {
 			return nil, fmt.Errorf("type not set")
+		} else if err := provisioner.Validate(); err != nil {
+			return nil, fmt.Errorf("provisioner '%s': %w", provisioner.Uri, err)
 		}
 
 		slog.Debug(fmt.Sprintf("Loaded provisioner %s", provisioner.Uri))
@@ -92,14 +97,14 @@ func LoadProvisioners(raw []byte) ([]provisioners.Provisioner, error) {
 }
 
 // SaveProvisionerToDirectory saves the provisioner content (data) from the provisionerUrl to a new provisioners file
-// in the path directory.
-func SaveProvisionerToDirectory(path string, provisionerUrl string, data []byte) error {
+// in the path directory, and returns the name of the new file.
+func SaveProvisionerToDirectory(path string, provisionerUrl string, data []byte) (string, error) {
 	// First validate whether this file contains valid provisioner data.
 	if _, err := LoadProvisioners(data); err != nil {
-		return fmt.Errorf("invalid provisioners file: %w", err)
+		return "", fmt.Errorf("invalid provisioners file: %w", err)
 	}
 	// Append a heading indicating the source and time
-	data = append([]byte(fmt.Sprintf("# Downloaded from %s at %s\n", provisionerUrl, time.Now())), data...)
+	data = append([]byte(fmt.Sprintf(downloadedHeaderPrefix+"%s at %s\n", provisionerUrl, time.Now())), data...)
 	hashValue := sha256.Sum256([]byte(provisionerUrl))
 	hashName := base64.RawURLEncoding.EncodeToString(hashValue[:16]) + ProvisionersFileSuffix
 	// We use a time prefix to always put the most recently downloaded files first lexicographically. So subtract
@@ -109,25 +114,25 @@ func SaveProvisionerToDirectory(path string, provisionerUrl string, data []byte)
 	targetPath := filepath.Join(path, timePrefix+"."+hashName)
 	tmpPath := targetPath + ".tmp"
 	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+		return "", fmt.Errorf("failed to write file: %w", err)
 	} else if err := os.Rename(tmpPath, targetPath); err != nil {
-		return fmt.Errorf("failed to rename temp file: %w", err)
+		return "", fmt.Errorf("failed to rename temp file: %w", err)
 	}
 	slog.Info(fmt.Sprintf("Wrote provisioner from '%s' to %s", provisionerUrl, targetPath))
 
 	// Remove any old files that have the same source.
 	if items, err := os.ReadDir(path); err != nil {
-		return err
+		return "", err
 	} else {
 		for _, item := range items {
 			if strings.HasSuffix(item.Name(), hashName) && !strings.HasPrefix(item.Name(), timePrefix) {
 				if err := os.Remove(filepath.Join(path, item.Name())); err != nil {
-					return fmt.Errorf("failed to remove old copy of provisioner loaded from '%s': %w", provisionerUrl, err)
+					return "", fmt.Errorf("failed to remove old copy of provisioner loaded from '%s': %w", provisionerUrl, err)
 				}
 				slog.Debug(fmt.Sprintf("Removed old copy of provisioner loaded from '%s'", provisionerUrl))
 			}
 		}
 	}
 
-	return nil
+	return filepath.Base(targetPath), nil
 }
\ No newline at end of file
@@ -0,0 +1,309 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LockFileName is the lock of the provisioners files in the state directory. It records the uri which each file was
+// installed from and the digest of its content.
+const LockFileName = "provisioners.lock"
+
+// legacySourcesFileName is the manifest of the installed provisioners files which the lock replaces, it is converted
+// to the lock when the lock does not exist yet and removed when the lock is persisted.
+const legacySourcesFileName = "provisioners.sources.yaml"
+
+// StdinUri is the uri of the provisioners read from the standard input, they can't be fetched again.
+const StdinUri = "-"
+
+// downloadedHeaderPrefix starts the line that SaveProvisionerToDirectory prepends to the files, it is not part of
+// their digest since it holds the download time.
+const downloadedHeaderPrefix = "# Downloaded from "
+
+// Lock records where the provisioners files come from and the digest of their content.
+type Lock struct {
+	// Sources are the uris which provisioners files were installed from.
+	Sources []LockedSource `yaml:"sources"`
+	// Local are the other provisioners files of the directory, like the default provisioners.
+	Local []LockedFile `yaml:"local,omitempty"`
+}
+
+// LockedSource is a uri and the provisioners files which were saved from it.
+type LockedSource struct {
+	Uri string `yaml:"uri"`
+	// Revision is the manifest digest of an oci:// uri, or the commit of a git uri, when the files were installed.
+	Revision string       `yaml:"revision,omitempty"`
+	Files    []LockedFile `yaml:"files"`
+}
+
+// LockedFile is a provisioners file and the sha256 digest of its content.
+type LockedFile struct {
+	Name   string `yaml:"name"`
+	Sha256 string `yaml:"sha256"`
+}
+
+// LoadLock loads the lock of the directory, it is empty if the file does not exist.
+func LoadLock(path string) (*Lock, error) {
+	raw, err := os.ReadFile(filepath.Join(path, LockFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return loadLegacySources(path)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read provisioners lock: %w", err)
+	}
+	var out Lock
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode provisioners lock: %w", err)
+	}
+	return &out, nil
+}
+
+// loadLegacySources converts the legacy sources manifest of the directory to a lock, with the digests of the files
+// on disk. The lock is empty if the manifest does not exist.
+func loadLegacySources(path string) (*Lock, error) {
+	out := &Lock{Sources: make([]LockedSource, 0)}
+	raw, err := os.ReadFile(filepath.Join(path, legacySourcesFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return out, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read provisioners sources: %w", err)
+	}
+	var legacy struct {
+		Sources []struct {
+			Uri   string   `yaml:"uri"`
+			Files []string `yaml:"files"`
+		} `yaml:"sources"`
+	}
+	if err := yaml.Unmarshal(raw, &legacy); err != nil {
+		return nil, fmt.Errorf("failed to decode provisioners sources: %w", err)
+	}
+	for _, legacySource := range legacy.Sources {
+		source := LockedSource{Uri: legacySource.Uri, Files: make([]LockedFile, 0, len(legacySource.Files))}
+		for _, fileName := range legacySource.Files {
+			digest, err := fileDigest(path, fileName)
+			if errors.Is(err, os.ErrNotExist) {
+				slog.Warn(fmt.Sprintf("Provisioners file %s installed from '%s' does not exist, it is not locked", fileName, legacySource.Uri))
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			source.Files = append(source.Files, LockedFile{Name: fileName, Sha256: digest})
+		}
+		out.Sources = append(out.Sources, source)
+	}
+	slog.Info(fmt.Sprintf("Converted the provisioners sources of %s to %s", legacySourcesFileName, LockFileName))
+	return out, nil
+}
+
+// Persist writes the lock to the directory, after recording the digests of the local provisioners files.
+func (l *Lock) Persist(path string) error {
+	if err := l.lockLocalFiles(path); err != nil {
+		return err
+	}
+	out := new(bytes.Buffer)
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(2)
+	if err := enc.Encode(l); err != nil {
+		return fmt.Errorf("failed to encode provisioners lock: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(path, LockFileName), out.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write provisioners lock: %w", err)
+	}
+	if err := os.Remove(filepath.Join(path, legacySourcesFileName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove provisioners sources: %w", err)
+	}
+	return nil
+}
+
+// Get returns the source of the uri, or nil if the uri is not recorded.
+func (l *Lock) Get(uri string) *LockedSource {
+	if i := slices.IndexFunc(l.Sources, func(source LockedSource) bool {
+		return source.Uri == uri
+	}); i >= 0 {
+		return &l.Sources[i]
+	}
+	return nil
+}
+
+// FileSource returns the uri which the provisioners file was saved from, or an empty string.
+func (l *Lock) FileSource(fileName string) string {
+	for _, source := range l.Sources {
+		if slices.ContainsFunc(source.Files, func(file LockedFile) bool {
+			return file.Name == fileName
+		}) {
+			return source.Uri
+		}
+	}
+	return ""
+}
+
+// Install fetches the provisioners files of the uri, saves them to the directory, and records them as the files of
+// the uri. The files previously saved from the uri and not saved again are removed.
+func (l *Lock) Install(ctx context.Context, path string, uri string) error {
+	files, revision, err := fetchFiles(ctx, uri)
+	if err != nil {
+		return fmt.Errorf("failed to load provisioners from %s: %w", uri, err)
+	}
+	saved := make([]LockedFile, 0, len(files))
+	for _, f := range files {
+		saveFilename := f.URI
+		if saveFilename == StdinUri {
+			saveFilename = "from-stdin.provisioners.yaml"
+		}
+		fileName, err := SaveProvisionerToDirectory(path, saveFilename, f.Content)
+		if err != nil {
+			return fmt.Errorf("failed to save provisioner from %s: %w", f.URI, err)
+		}
+		digest := sha256.Sum256(f.Content)
+		saved = append(saved, LockedFile{Name: fileName, Sha256: hex.EncodeToString(digest[:])})
+	}
+
+	if source := l.Get(uri); source != nil {
+		for _, file := range source.Files {
+			if !slices.ContainsFunc(saved, func(savedFile LockedFile) bool {
+				return savedFile.Name == file.Name
+			}) {
+				if err := removeFile(path, file.Name); err != nil {
+					return err
+				}
+			}
+		}
+		source.Revision = revision
+		source.Files = saved
+	} else {
+		l.Sources = append(l.Sources, LockedSource{Uri: uri, Revision: revision, Files: saved})
+	}
+	return nil
+}
+
+// Remove deletes the provisioners files saved from the uri and the record of the uri.
+func (l *Lock) Remove(path string, uri string) error {
+	source := l.Get(uri)
+	if source == nil {
+		return fmt.Errorf("no provisioners installed from '%s'", uri)
+	}
+	for _, file := range source.Files {
+		if err := removeFile(path, file.Name); err != nil {
+			return err
+		}
+	}
+	l.Sources = slices.DeleteFunc(l.Sources, func(source LockedSource) bool {
+		return source.Uri == uri
+	})
+	return nil
+}
+
+// Verify returns an error if the provisioners files of the directory don't match the lock: a locked file is missing
+// or has a different digest, or a file is not locked.
+func (l *Lock) Verify(path string) error {
+	locked := slices.Clone(l.Local)
+	for _, source := range l.Sources {
+		locked = append(locked, source.Files...)
+	}
+	for _, file := range locked {
+		digest, err := fileDigest(path, file.Name)
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("locked provisioners file '%s' does not exist", file.Name)
+		} else if err != nil {
+			return err
+		} else if digest != file.Sha256 {
+			return fmt.Errorf("provisioners file '%s' does not match its locked digest", file.Name)
+		}
+	}
+	fileNames, err := provisionersFileNames(path)
+	if err != nil {
+		return err
+	}
+	for _, fileName := range fileNames {
+		if !slices.ContainsFunc(locked, func(file LockedFile) bool {
+			return file.Name == fileName
+		}) {
+			return fmt.Errorf("provisioners file '%s' is not locked", fileName)
+		}
+	}
+	return nil
+}
+
+// lockLocalFiles records the digests of the provisioners files of the directory which were not installed from a uri.
+func (l *Lock) lockLocalFiles(path string) error {
+	fileNames, err := provisionersFileNames(path)
+	if err != nil {
+		return err
+	}
+	l.Local = make([]LockedFile, 0)
+	for _, fileName := range fileNames {
+		if l.FileSource(fileName) != "" {
+			continue
+		}
+		digest, err := fileDigest(path, fileName)
+		if err != nil {
+			return err
+		}
+		l.Local = append(l.Local, LockedFile{Name: fileName, Sha256: digest})
+	}
+	return nil
+}
+
+func provisionersFileNames(path string) ([]string, error) {
+	items, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if !item.IsDir() && strings.HasSuffix(item.Name(), ProvisionersFileSuffix) {
+			out = append(out, item.Name())
+		}
+	}
+	return out, nil
+}
+
+// fileDigest returns the hex sha256 digest of a provisioners file, without the download header.
+func fileDigest(path string, fileName string) (string, error) {
+	raw, err := os.ReadFile(filepath.Join(path, fileName))
+	if err != nil {
+		return "", err
+	}
+	if bytes.HasPrefix(raw, []byte(downloadedHeaderPrefix)) {
+		if _, rest, ok := bytes.Cut(raw, []byte("\n")); ok {
+			raw = rest
+		}
+	}
+	digest := sha256.Sum256(raw)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+func removeFile(path string, fileName string) error {
+	if err := os.Remove(filepath.Join(path, fileName)); err == nil {
+		slog.Info(fmt.Sprintf("Removed provisioners file %s", fileName))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove provisioners file '%s': %w", fileName, err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loader
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/score-spec/score-go/uriget"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+	"oras.land/oras-go/v2/registry/remote/retry"
+)
+
+// fetchFiles fetches the provisioners files of the uri, with the revision they were fetched from. An oci:// uri is
+// fetched by the manifest digest its reference resolves to, and a git-ssh:// or git-https:// uri is checked out to read
+// the commit of the files. The other uris have no revision and are only pinned by the digests of their files.
+func fetchFiles(ctx context.Context, uri string) ([]uriget.FileContent, string, error) {
+	if u, err := url.Parse(uri); err == nil {
+		switch strings.ToLower(u.Scheme) {
+		case "oci":
+			return fetchOciFiles(ctx, u, uri)
+		case "git-ssh", "git-https":
+			return fetchGitFiles(ctx, u, uri)
+		}
+	}
+	files, err := uriget.GetFiles(ctx, uri)
+	return files, "", err
+}
+
+// fetchOciFiles fetches the file of the oci:// uri by the digest its reference resolves to, so that the digest is the
+// one of the fetched content even if the tag moves.
+func fetchOciFiles(ctx context.Context, u *url.URL, uri string) ([]uriget.FileContent, string, error) {
+	ref, err := registry.ParseReference(u.Host + u.Path)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid artifact URL: %w", err)
+	}
+	digest, err := resolveOciDigest(ctx, ref)
+	if err != nil {
+		return nil, "", err
+	}
+	ref.Reference = digest
+	pinnedUri := "oci://" + ref.String()
+	if u.Fragment != "" {
+		pinnedUri += "#" + u.Fragment
+	}
+	files, err := uriget.GetFiles(ctx, pinnedUri)
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range files {
+		if files[i].URI == pinnedUri {
+			files[i].URI = uri
+		}
+	}
+	return files, digest, nil
+}
+
+// resolveOciDigest resolves the reference to the digest of its manifest, with the same defaults and credentials as the
+// files are fetched with.
+func resolveOciDigest(ctx context.Context, ref registry.Reference) (string, error) {
+	if ref.Reference == "" {
+		ref.Reference = "latest"
+	}
+	if strings.Contains(ref.Reference, ":") {
+		return ref.Reference, nil
+	}
+	credStore, err := credentials.NewStoreFromDocker(credentials.StoreOptions{})
+	if err != nil {
+		slog.Warn(fmt.Sprintf("Unable to load Docker credentials, continuing without auth: %v", err))
+	}
+	remoteRepo, err := remote.NewRepository(ref.String())
+	if err != nil {
+		return "", fmt.Errorf("connection to remote repository failed: %w", err)
+	}
+	remoteRepo.PlainHTTP = strings.HasPrefix(ref.Registry, "localhost") || strings.HasPrefix(ref.Registry, "127.0.0.1")
+	remoteRepo.Client = &auth.Client{
+		Client:     retry.DefaultClient,
+		Cache:      auth.NewCache(),
+		Credential: credentials.Credential(credStore),
+	}
+	desc, err := remoteRepo.Resolve(ctx, ref.Reference)
+	if err != nil {
+		return "", fmt.Errorf("manifest resolution failed: %w", err)
+	}
+	return desc.Digest.String(), nil
+}
+
+// fetchGitFiles checks out the path of the git uri from the HEAD of the remote repository, like the git uris of
+// init --provisioners, and reads the files and the commit from the same checkout.
+func fetchGitFiles(ctx context.Context, u *url.URL, uri string) ([]uriget.FileContent, string, error) {
+	remoteUrl := *u
+	remoteUrl.Scheme = strings.TrimPrefix(strings.ToLower(u.Scheme), "git-")
+	remoteUrl.RawQuery, remoteUrl.Fragment = "", ""
+	repoPath, subPath, ok := strings.Cut(u.Path, ".git/")
+	subPath = strings.TrimSuffix(subPath, "/")
+	if !ok || repoPath == "" || subPath == "" {
+		return nil, "", fmt.Errorf("invalid git url, expected a path with ../<REPO>.git/<PATH>")
+	}
+	remoteUrl.Path = repoPath + ".git"
+
+	td, err := os.MkdirTemp("", "score-radius")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to make temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(td) }()
+	for _, args := range [][]string{
+		{"init"},
+		{"remote", "add", "origin", remoteUrl.String()},
+		{"sparse-checkout", "set", "--no-cone", "--sparse-index", subPath},
+		{"pull", "origin", "HEAD", "--depth=1"},
+	} {
+		c := exec.CommandContext(ctx, "git", args...)
+		c.Dir = td
+		if output, err := c.CombinedOutput(); err != nil {
+			return nil, "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+		}
+	}
+	c := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	c.Dir = td
+	output, err := c.Output()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read the checked out commit: %w", err)
+	}
+
+	targetPath := filepath.Join(td, subPath)
+	files, err := uriget.GetFiles(ctx, targetPath)
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range files {
+		if files[i].URI == targetPath {
+			files[i].URI = uri
+		} else {
+			files[i].URI = subPath + "/" + filepath.Base(files[i].URI)
+		}
+	}
+	return files, strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loader
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/score-spec/score-radius/internal/provisioners"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Issue is a problem found in a provisioners file.
+type Issue struct {
+	File     string `json:"file"`
+	Uri      string `json:"uri,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// ValidateFiles loads the provisioners files in the given order, like LoadProvisionersFromDirectory, and returns the
+// issues found in them. The files which fail to load are errors. A provisioner matching the same resources as a
+// previous one in the same file is an error since it is never used, and a warning when it is in another file since it
+// is shadowed. A declared param which no template reads is a warning.
+func ValidateFiles(paths []string) []Issue {
+	issues := make([]Issue, 0)
+	type loaded struct {
+		file        string
+		provisioner provisioners.Provisioner
+	}
+	all := make([]loaded, 0)
+	for _, path := range paths {
+		file := filepath.Base(path)
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			issues = append(issues, Issue{File: file, Severity: SeverityError, Message: err.Error()})
+			continue
+		}
+		p, err := LoadProvisioners(raw)
+		if err != nil {
+			issues = append(issues, Issue{File: file, Severity: SeverityError, Message: err.Error()})
+			continue
+		}
+		for _, provisioner := range p {
+			all = append(all, loaded{file: file, provisioner: provisioner})
+		}
+	}
+
+	for i, current := range all {
+		if j := slices.IndexFunc(all[:i], func(previous loaded) bool {
+			return matchKey(previous.provisioner) == matchKey(current.provisioner)
+		}); j >= 0 {
+			previous := all[j]
+			if previous.file == current.file {
+				issues = append(issues, Issue{
+					File: current.file, Uri: current.provisioner.Uri, Severity: SeverityError,
+					Message: fmt.Sprintf("never used, '%s' in the same file matches the same resources", previous.provisioner.Uri),
+				})
+			} else {
+				issues = append(issues, Issue{
+					File: current.file, Uri: current.provisioner.Uri, Severity: SeverityWarning,
+					Message: fmt.Sprintf("shadowed by '%s' in %s which matches the same resources", previous.provisioner.Uri, previous.file),
+				})
+			}
+		}
+		for _, name := range unusedParams(current.provisioner) {
+			issues = append(issues, Issue{
+				File: current.file, Uri: current.provisioner.Uri, Severity: SeverityWarning,
+				Message: fmt.Sprintf("param '%s' is declared but not used by any template", name),
+			})
+		}
+	}
+	return issues
+}
+
+// matchKey identifies the resources matched by a provisioner.
+func matchKey(p provisioners.Provisioner) string {
+	workloads := slices.Clone(p.Workloads)
+	slices.Sort(workloads)
+	return fmt.Sprintf("%s|%s|%s|%v|%v", p.ResType, p.Class, p.ResId, workloads, p.MetadataSelector)
+}
+
+// unusedParams returns the declared params of a template provisioner which no template reads, either as .Params.name
+// or through the name in quotes. The params of the other provisioners are not known to be unused.
+func unusedParams(p provisioners.Provisioner) []string {
+	if u, _ := url.Parse(p.Uri); u == nil || u.Scheme != "template" {
+		return nil
+	}
+	params := p.EffectiveParams()
+	if len(params) == 0 {
+		return nil
+	}
+	templates := new(strings.Builder)
+	for _, t := range p.Templates() {
+		templates.WriteString(t[1])
+		templates.WriteString("\n")
+	}
+	// a template passing the whole params map may read any of them
+	if regexp.MustCompile(`\.Params\b([^.\w]|$)`).MatchString(templates.String()) {
+		return nil
+	}
+	out := make([]string, 0)
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		if slices.Contains(provisioners.ConnectionParams, name) {
+			continue
+		}
+		used := regexp.MustCompile(`\.Params\.` + regexp.QuoteMeta(name) + `\b|"` + regexp.QuoteMeta(name) + `"`)
+		if !used.MatchString(templates.String()) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provisioners
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/score-spec/score-go/framework"
+)
+
+// AnyClass is the class of the provisioners which match any class of their resource type.
+const AnyClass = "*"
+
+// MatchProvisioner returns the provisioner of a resource and a description of why it was chosen. Among the
+// provisioners of the resource type, the most specific one wins: a matching id first, then an exact class rather
+// than the wildcard class, then a workload selector, then a metadata selector. The first provisioner in the list wins
+// between equally specific provisioners. A fallback provisioner is only chosen when no other provisioner matches. It
+// returns nil if no provisioner matches.
+func MatchProvisioner(provisioners []Provisioner, resUid framework.ResourceUid, sourceWorkload string, metadata map[string]interface{}) (*Provisioner, string) {
+	var best *Provisioner
+	var bestScore int
+	var bestReasons []string
+	for i, provisioner := range provisioners {
+		score, reasons, ok := provisioner.match(resUid, sourceWorkload, metadata)
+		if ok && (best == nil || score > bestScore) {
+			best, bestScore, bestReasons = &provisioners[i], score, reasons
+		}
+	}
+	if best == nil {
+		if i := slices.IndexFunc(provisioners, func(provisioner Provisioner) bool {
+			return provisioner.Fallback
+		}); i >= 0 {
+			return &provisioners[i], fmt.Sprintf("type '%s', fallback", resUid.Type())
+		}
+		return nil, ""
+	}
+	return best, strings.Join(bestReasons, ", ")
+}
+
+// match returns whether the provisioner matches the resource, its specificity, and the criteria which matched.
+func (p *Provisioner) match(resUid framework.ResourceUid, sourceWorkload string, metadata map[string]interface{}) (int, []string, bool) {
+	if p.ResType != resUid.Type() {
+		return 0, nil, false
+	}
+	score := 0
+	reasons := []string{fmt.Sprintf("type '%s'", p.ResType)}
+	if p.ResId != "" {
+		if p.ResId != resUid.Id() {
+			return 0, nil, false
+		}
+		score += 8
+		reasons = append(reasons, fmt.Sprintf("id '%s'", p.ResId))
+	}
+	if p.Class == AnyClass {
+		reasons = append(reasons, "any class")
+	} else if p.Class == resUid.Class() {
+		score += 4
+		reasons = append(reasons, fmt.Sprintf("class '%s'", p.Class))
+	} else {
+		return 0, nil, false
+	}
+	if len(p.Workloads) > 0 {
+		if !slices.Contains(p.Workloads, sourceWorkload) {
+			return 0, nil, false
+		}
+		score += 2
+		reasons = append(reasons, fmt.Sprintf("workload '%s'", sourceWorkload))
+	}
+	if len(p.MetadataSelector) > 0 {
+		if !containsSubset(metadata, p.MetadataSelector) {
+			return 0, nil, false
+		}
+		score += 1
+		reasons = append(reasons, fmt.Sprintf("metadata %s", strings.Join(slices.Sorted(maps.Keys(p.MetadataSelector)), ", ")))
+	}
+	return score, reasons, true
+}
+
+// containsSubset returns whether every key of the selector is in the value, nested maps are compared recursively and
+// the other values by their string form so that 1 matches "1".
+func containsSubset(value map[string]interface{}, selector map[string]interface{}) bool {
+	for k, expected := range selector {
+		actual, ok := value[k]
+		if !ok {
+			return false
+		}
+		expectedMap, isMap := expected.(map[string]interface{})
+		if actualMap, ok := actual.(map[string]interface{}); isMap && ok {
+			if !containsSubset(actualMap, expectedMap) {
+				return false
+			}
+		} else if isMap || fmt.Sprint(actual) != fmt.Sprint(expected) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provisioners
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParamTypes are the types which a param schema can declare, like in JSON schema.
+var ParamTypes = []string{"string", "number", "integer", "boolean", "array", "object"}
+
+// ParamSchema describes a param of a provisioner.
+type ParamSchema struct {
+	// Type is one of ParamTypes, any value is accepted when it is empty.
+	Type        string `yaml:"type,omitempty" json:"type,omitempty"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Default is the value of the param when the resource does not set it.
+	Default interface{} `yaml:"default,omitempty" json:"default,omitempty"`
+	// Enum is the list of allowed values.
+	Enum     []interface{} `yaml:"enum,omitempty" json:"enum,omitempty"`
+	Required bool          `yaml:"required,omitempty" json:"required,omitempty"`
+}
+
+// ParamsSchema are the params declared by a provisioner, by name. It is decoded from either a list of param names
+// accepting any value, or a map of param names to their schema.
+type ParamsSchema map[string]ParamSchema
+
+func (p *ParamsSchema) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		var names []string
+		if err := node.Decode(&names); err != nil {
+			return err
+		}
+		*p = make(ParamsSchema, len(names))
+		for _, name := range names {
+			(*p)[name] = ParamSchema{}
+		}
+		return nil
+	}
+	var schemas map[string]ParamSchema
+	if err := node.Decode(&schemas); err != nil {
+		return err
+	}
+	for name, schema := range schemas {
+		if err := schema.validate(); err != nil {
+			return fmt.Errorf("param '%s': %w", name, err)
+		}
+	}
+	*p = schemas
+	return nil
+}
+
+// JSONSchema returns the schema of the two yaml forms of the params.
+func (p ParamsSchema) JSONSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"oneOf": []interface{}{
+			map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			map[string]interface{}{
+				"type": "object",
+				"additionalProperties": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"type":        map[string]interface{}{"enum": ParamTypes},
+						"description": map[string]interface{}{"type": "string"},
+						"default":     map[string]interface{}{},
+						"enum":        map[string]interface{}{"type": "array"},
+						"required":    map[string]interface{}{"type": "boolean"},
+					},
+					"additionalProperties": false,
+				},
+			},
+		},
+	}
+}
+
+// Names returns the sorted names of the params.
+func (p ParamsSchema) Names() []string {
+	return slices.Sorted(maps.Keys(p))
+}
+
+// validate checks that the default and the allowed values of the schema match its type.
+func (s ParamSchema) validate() error {
+	if s.Type != "" && !slices.Contains(ParamTypes, s.Type) {
+		return fmt.Errorf("unknown type '%s', expected one of: %s", s.Type, strings.Join(ParamTypes, ", "))
+	}
+	for _, value := range s.Enum {
+		if !matchesParamType(s.Type, value) {
+			return fmt.Errorf("enum value '%v' is not of type %s", value, s.Type)
+		}
+	}
+	if s.Default != nil {
+		if err := s.check(s.Default); err != nil {
+			return fmt.Errorf("default: %w", err)
+		}
+	}
+	return nil
+}
+
+// check returns an error if the value does not match the type or the allowed values of the schema.
+func (s ParamSchema) check(value interface{}) error {
+	if !matchesParamType(s.Type, value) {
+		return fmt.Errorf("expected type %s but got %s", s.Type, describeParamValue(value))
+	}
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(allowed interface{}) bool {
+		return paramValuesEqual(allowed, value)
+	}) {
+		allowed := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			allowed[i] = fmt.Sprint(v)
+		}
+		return fmt.Errorf("'%v' is not one of: %s", value, strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+func matchesParamType(paramType string, value interface{}) bool {
+	switch paramType {
+	case "":
+		return true
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	case "integer":
+		f, ok := toFloat(value)
+		return ok && f == math.Trunc(f)
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	}
+	return false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, !math.IsNaN(v) && !math.IsInf(v, 0)
+	}
+	return 0, false
+}
+
+// paramValuesEqual compares values decoded from yaml or json, where numbers may have different Go types.
+func paramValuesEqual(a, b interface{}) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+	switch a.(type) {
+	case string, bool:
+		return a == b
+	}
+	return false
+}
+
+func describeParamValue(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("the string '%s'", value)
+	case bool:
+		return "a boolean"
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	}
+	if _, ok := toFloat(value); ok {
+		return "a number"
+	}
+	return fmt.Sprintf("a %T", value)
+}
@@ -16,47 +16,212 @@ package provisioners
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"html/template"
 	"log/slog"
 	"maps"
+	"net/url"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
+	"text/template"
+	"unicode"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/go-viper/mapstructure/v2"
 	"gopkg.in/yaml.v3"
 
 	"github.com/score-spec/score-go/framework"
+	scoretypes "github.com/score-spec/score-go/types"
 
+	"github.com/score-spec/score-radius/internal/bicep"
 	"github.com/score-spec/score-radius/internal/state"
 )
 
 type Provisioner struct {
-	Uri         string `yaml:"uri"`
-	ResType     string `yaml:"type"`
-	Format      string `yaml:"format"`
-	Class       string `yaml:"class"`
-	Description string `yaml:"description,omitempty"`
+	Uri     string `yaml:"uri"`
+	ResType string `yaml:"type"`
+	Format  string `yaml:"format"`
+	// Class is the class of the resources, or * to match any class.
+	Class string `yaml:"class"`
+	// ResId restricts the provisioner to the resource with this id, e.g. a shared resource.
+	ResId string `yaml:"id,omitempty"`
+	// Workloads restricts the provisioner to the resources declared by one of these workloads.
+	Workloads []string `yaml:"workloads,omitempty"`
+	// MetadataSelector restricts the provisioner to the resources whose metadata contains these values.
+	MetadataSelector map[string]interface{} `yaml:"metadata_selector,omitempty"`
+	Description      string                 `yaml:"description,omitempty"`
 	// The InitTemplate is always evaluated first, it is used as temporary or working set data that may be needed in the
 	// later templates. It has access to the resource inputs and previous state.
-	InitTemplate      string `yaml:"init,omitempty"`
-	ManifestsTemplate string `yaml:"manifests,omitempty"`
-	// Params is a list of inputs that the provisioner expects to be passed in.
-	Params []string `yaml:"params,omitempty"`
+	InitTemplate string `yaml:"init,omitempty"`
+	// The StateTemplate is evaluated after the InitTemplate, its result replaces the state of the resource which is
+	// persisted and available to the next generation through .State.
+	StateTemplate string `yaml:"state,omitempty"`
+	// The SharedStateTemplate is evaluated after the StateTemplate, its result is merged into the shared state of all
+	// the resources, a null value removes a key.
+	SharedStateTemplate string `yaml:"shared,omitempty"`
+	ManifestsTemplate   string `yaml:"manifests,omitempty"`
+	// The SharedManifestsTemplate is evaluated once after all the resources are provisioned, with the data of each
+	// resource provisioned by this provisioner as .Resources, so that their Bicep resources can be merged, like the
+	// routes of a host into one gateway.
+	SharedManifestsTemplate string `yaml:"shared_manifests,omitempty"`
+	// Params are the inputs that the provisioner expects to be passed in, either a list of names or a schema by name.
+	Params ParamsSchema `yaml:"params,omitempty"`
+	// RequiredParams is a list of inputs that must be passed in, they don't need to be repeated in Params.
+	RequiredParams []string `yaml:"required_params,omitempty"`
 	// Outputs is a list of outputs that the provisioner should return.
 	Outputs []string `yaml:"expected_outputs,omitempty"`
+	// SecretOutputs is a list of outputs holding secrets, they are never written in plain container definitions.
+	SecretOutputs []string `yaml:"secret_outputs,omitempty"`
+	// NoConnection indicates that the resource has no Radius resource which the containers can connect to.
+	NoConnection bool `yaml:"no_connection,omitempty"`
+	// ConnectionOutput is the output holding the source of the connections to the resource, like the url of another
+	// container, instead of the id of its Radius resource.
+	ConnectionOutput string `yaml:"connection_output,omitempty"`
 	// Outputs is a list of actual outputs evaluated from the template.
 	OutputsTemplate string `yaml:"outputs,omitempty"`
+	// Args are the arguments passed to the executable of a cmd:// provisioner.
+	Args []string `yaml:"args,omitempty"`
+	// PortableType is the Radius portable resource type declared by a recipe:// provisioner, with an optional api
+	// version, e.g. Applications.Datastores/redisCaches.
+	PortableType string `yaml:"portable_type,omitempty"`
+	// Recipe is the name of the environment recipe of a recipe:// provisioner, DefaultRecipeName when it is empty.
+	Recipe string `yaml:"recipe,omitempty"`
+	// Fallback provisioners match the resources of any type which no other provisioner matches, like ExtenderFallback.
+	Fallback bool `yaml:"-"`
+
+	// SourceFile is the name of the file the provisioner was loaded from.
+	SourceFile string `yaml:"-"`
+
+	// environmentParams collects the params read by an environment:// provisioner.
+	environmentParams *EnvironmentParams
+}
+
+// Schemes describes the uri schemes of the provisioners, in the order they are documented. The environment:// and
+// extender:// provisioners are built in and can't be declared in the provisioners files.
+var Schemes = [][2]string{
+	{"template", "a template provisioner"},
+	{cmdScheme, "an executable"},
+	{recipeScheme, "a Radius environment recipe"},
+	{environmentScheme, "the built-in provisioner of the environment resources"},
+	{extenderScheme, "the built-in provisioner of 'generate --extender-fallback'"},
+}
+
+// builtInSchemes are the uri schemes of the built-in provisioners.
+var builtInSchemes = []string{environmentScheme, extenderScheme}
+
+// SupportedFormats are the formats of the manifests a provisioner can declare, an empty format is Bicep.
+var SupportedFormats = []string{"bicep"}
+
+// ConnectionParams are the params read by the connections of the containers rather than by the provisioners.
+var ConnectionParams = []string{"disableDefaultEnvVars"}
+
+// Templates returns the templates of the provisioner by name, in their evaluation order. The empty templates are
+// skipped.
+func (p *Provisioner) Templates() [][2]string {
+	out := make([][2]string, 0, 6)
+	for _, t := range [][2]string{
+		{"init", p.InitTemplate},
+		{"state", p.StateTemplate},
+		{"shared", p.SharedStateTemplate},
+		{"outputs", p.OutputsTemplate},
+		{"manifests", p.ManifestsTemplate},
+		{"shared_manifests", p.SharedManifestsTemplate},
+	} {
+		if strings.TrimSpace(t[1]) != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// bicepResourceTypePattern finds the types of the resources declared in a Bicep template, e.g.
+// resource {{ .SymbolicName }} 'Applications.Datastores/redisCaches@2023-10-01-preview' = {
+var bicepResourceTypePattern = regexp.MustCompile(`(?m)^\s*resource\s+(?:\{\{.*?\}\}|\S+)\s+'([^'@]+)@[^']*'`)
+
+// ResourceTypes returns the sorted Bicep resource types declared by the manifests template, or the portable resource
+// type of a recipe provisioner. They are not known for the command provisioners.
+func (p *Provisioner) ResourceTypes() []string {
+	out := make([]string, 0)
+	if p.PortableType != "" {
+		resType, _, _ := strings.Cut(p.PortableType, "@")
+		out = append(out, resType)
+	}
+	for _, match := range bicepResourceTypePattern.FindAllStringSubmatch(p.ManifestsTemplate+"\n"+p.SharedManifestsTemplate, -1) {
+		if !slices.Contains(out, match[1]) {
+			out = append(out, match[1])
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Validate checks that the format of the provisioner is supported and that its templates parse, with the same
+// functions as when they are rendered. A recipe provisioner must declare its portable resource type instead.
+func (p *Provisioner) Validate() error {
+	if p.Format != "" && !slices.Contains(SupportedFormats, p.Format) {
+		return fmt.Errorf("unsupported format '%s', expected one of: %s", p.Format, strings.Join(SupportedFormats, ", "))
+	}
+	u, _ := url.Parse(p.Uri)
+	if u != nil && slices.Contains(builtInSchemes, u.Scheme) {
+		return fmt.Errorf("the %s:// provisioners are built in and can't be declared in a provisioners file", u.Scheme)
+	} else if u != nil && u.Scheme == recipeScheme {
+		return p.validateRecipe()
+	}
+	for _, t := range p.Templates() {
+		if _, err := newTemplate(t[0]).Parse(t[1]); err != nil {
+			return fmt.Errorf("%s template: %w", t[0], withTemplateLine(err, t[1]))
+		}
+	}
+	return nil
+}
+
+// newTemplate returns an empty template with the functions available to the provisioners.
+func newTemplate(name string) *template.Template {
+	return template.New(name).Funcs(sprig.TxtFuncMap()).Funcs(bicep.FuncMap())
 }
 
 type Data struct {
-	Id           string
-	Init         map[string]interface{}
+	Uid   string
+	Type  string
+	Class string
+	Id    string
+	// SymbolicName is the Bicep symbolic name of the resource, it is unique within the generated Bicep file.
+	SymbolicName string
+	// Name is the Radius name of the resource, derived from the symbolic name so that it is unique too.
+	Name string
+	// Params are the params of the resource, after substitution.
+	Params   map[string]interface{}
+	Metadata map[string]interface{}
+	Init     map[string]interface{}
+	// State is the state of the resource, as previously persisted and then as returned by the state template.
+	State map[string]interface{}
+	// Shared is the state shared by all the resources.
+	Shared map[string]interface{}
+	// SourceWorkload is the name of the workload which declared the resource first.
+	SourceWorkload string
+	// WorkloadName is an alias of SourceWorkload.
 	WorkloadName string
+	// WorkloadServices are the services of all the workloads, by workload name.
+	WorkloadServices map[string]NetworkService
+}
+
+// SharedData is the data of the shared manifests template of a provisioner.
+type SharedData struct {
+	// Resources are the resources provisioned by the provisioner, in their provisioning order, with the Init and the
+	// State returned by their templates.
+	Resources []Data
+	Shared    map[string]interface{}
+}
+
+// NetworkService is the service of a workload, reachable through the name of its Applications.Core/containers.
+type NetworkService struct {
+	ServiceName string                            `json:"service_name"`
+	Ports       map[string]scoretypes.ServicePort `json:"ports"`
 }
 
-func ProvisionResources(currentState *state.State, provisioners []Provisioner) (string, *state.State, error) {
+func ProvisionResources(ctx context.Context, currentState *state.State, provisioners []Provisioner) (string, *state.State, error) {
 	out := currentState
 	manifests := ""
 
@@ -66,16 +231,47 @@ func ProvisionResources(currentState *state.State, provisioners []Provisioner) (
 		return "", nil, fmt.Errorf("failed to determine sort order for provisioning: %w", err)
 	}
 
+	workloadServices := make(map[string]NetworkService, len(out.Workloads))
+	for workloadName, workload := range out.Workloads {
+		ns := NetworkService{ServiceName: workloadName, Ports: make(map[string]scoretypes.ServicePort)}
+		if workload.Spec.Service != nil {
+			maps.Copy(ns.Ports, workload.Spec.Service.Ports)
+		}
+		workloadServices[workloadName] = ns
+	}
+
+	// the params read by the environment resources must not collide with the declared resources, nor with the names
+	// declared by the manifests of the provisioners, which are added as the manifests are generated
+	symbolicNames := state.SymbolicNames(out)
+	var environmentParams *EnvironmentParams
+	for _, provisioner := range provisioners {
+		if provisioner.environmentParams != nil {
+			environmentParams = provisioner.environmentParams
+		}
+	}
+	declare := func(manifest string, source string) error {
+		for _, name := range bicep.DeclaredNames(manifest) {
+			symbolicNames[name] = true
+			if environmentParams != nil && slices.Contains(environmentParams.Names(), name) {
+				return fmt.Errorf("%s: '%s' is already the Bicep param of an environment output", source, name)
+			}
+		}
+		return nil
+	}
+
+	// the resources of each provisioner with a shared manifests template, in the order of their provisioner first use
+	sharedProvisioners := make([]*Provisioner, 0)
+	sharedResources := make(map[*Provisioner][]Data)
+
 	out.Resources = maps.Clone(out.Resources)
 	for _, resUid := range orderedResources {
 		resState := out.Resources[resUid]
 
-		provisionerIndex := slices.IndexFunc(provisioners, func(provisioner Provisioner) bool {
-			return provisioner.ResType == resUid.Type() && provisioner.Class == resUid.Class()
-		})
-		if provisionerIndex < 0 {
+		provisioner, match := MatchProvisioner(provisioners, resUid, resState.SourceWorkload, resState.Metadata)
+		if provisioner == nil {
 			return "", nil, fmt.Errorf("resource '%s' is not supported by any provisioner. Please implement a custom resource provisioner to support this resource type '%s' with class '%s'", resUid, resUid.Type(), resUid.Class())
 		}
+		slog.Info(fmt.Sprintf("Resource %s is provisioned by '%s', matched on %s", resUid, provisioner.Uri, match))
 
 		var params map[string]interface{}
 		if len(resState.Params) > 0 {
@@ -90,53 +286,244 @@ func ProvisionResources(currentState *state.State, provisioners []Provisioner) (
 			}
 			params = rawParams.(map[string]interface{})
 		}
+		params, err = provisioner.checkParams(params)
+		if err != nil {
+			return "", nil, fmt.Errorf("resource '%s': %w", resUid, err)
+		}
 		resState.Params = params
-		provisioner := provisioners[provisionerIndex]
 		resState.ProvisionerUri = provisioner.Uri
+		resState.Extras.ProvisionerMatch = match
+		resState.Extras.SecretOutputs = provisioner.SecretOutputs
+		resState.Extras.NoConnection = provisioner.NoConnection
 
-		init := make(map[string]interface{})
-		data := Data{
-			Id:           resState.Id,
-			Init:         init,
-			WorkloadName: resState.SourceWorkload,
+		if resState.State == nil {
+			resState.State = make(map[string]interface{})
 		}
-
-		if err := renderTemplateAndDecode(provisioner.InitTemplate, &data, &data.Init); err != nil {
-			return "", nil, fmt.Errorf("init template failed: %w", err)
+		if out.SharedState == nil {
+			out.SharedState = make(map[string]interface{})
 		}
-
-		resState.Outputs = make(map[string]interface{})
-		if err := renderTemplateAndDecode(provisioner.OutputsTemplate, &data, &resState.Outputs); err != nil {
-			return "", nil, fmt.Errorf("outputs template failed: %w", err)
+		data := Data{
+			Uid:              string(resUid),
+			Type:             resState.Type,
+			Class:            resState.Class,
+			Id:               resState.Id,
+			SymbolicName:     resState.Extras.SymbolicName,
+			Name:             state.RadiusName(resState.Extras.SymbolicName),
+			Params:           params,
+			Metadata:         resState.Metadata,
+			Init:             make(map[string]interface{}),
+			State:            resState.State,
+			Shared:           out.SharedState,
+			SourceWorkload:   resState.SourceWorkload,
+			WorkloadName:     resState.SourceWorkload,
+			WorkloadServices: workloadServices,
 		}
 
-		var resourceManifest string
-		resourceManifest, err = generateResourceManifest(provisioner.ManifestsTemplate, data)
+		var result *provisionResult
+		if u, _ := url.Parse(provisioner.Uri); u != nil && u.Scheme == cmdScheme {
+			result, err = provisionCmd(ctx, *provisioner, data)
+		} else if u != nil && u.Scheme == recipeScheme {
+			result, err = provisionRecipe(*provisioner, data)
+		} else if u != nil && u.Scheme == extenderScheme {
+			result, err = provisionExtender(*provisioner, data)
+		} else if u != nil && u.Scheme == environmentScheme {
+			result, err = provisionEnvironment(*provisioner, data, symbolicNames)
+		} else {
+			result, err = provisionTemplate(*provisioner, data)
+		}
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to generate resource manifest %s: %w", resUid.Type(), err)
+			return "", nil, err
+		}
+		if result.State != nil {
+			resState.State = result.State
+		}
+		if len(result.SharedState) > 0 {
+			out.SharedState = patchMap(out.SharedState, result.SharedState)
 		}
+		if err := provisioner.checkOutputs(result.Outputs); err != nil {
+			return "", nil, fmt.Errorf("resource '%s': %w", resUid, err)
+		}
+		resState.Outputs = result.Outputs
+		resState.OutputLookupFunc = result.OutputLookup
+		if result.SecretOutputs != nil {
+			resState.Extras.SecretOutputs = result.SecretOutputs
+		}
+		resState.Extras.ConnectionSource = ""
+		if provisioner.ConnectionOutput != "" {
+			source, ok := result.Outputs[provisioner.ConnectionOutput].(string)
+			if !ok || source == "" {
+				return "", nil, fmt.Errorf("resource '%s': connection output '%s' of provisioner '%s' is not a string", resUid, provisioner.ConnectionOutput, provisioner.Uri)
+			}
+			resState.Extras.ConnectionSource = source
+		}
+		resourceManifest := strings.TrimSpace(result.Manifests)
 		slog.Info(fmt.Sprintf("Resource %s's manifests generated", resUid.Type()))
 
 		out.Resources[resUid] = resState
-		resourceManifest = "\n" + resourceManifest
-		manifests = manifests + resourceManifest
+		if err := declare(resourceManifest, fmt.Sprintf("resource '%s'", resUid)); err != nil {
+			return "", nil, err
+		}
+		if resourceManifest != "" {
+			manifests = manifests + "\n" + resourceManifest
+		}
+
+		if strings.TrimSpace(provisioner.SharedManifestsTemplate) != "" {
+			if _, ok := sharedResources[provisioner]; !ok {
+				sharedProvisioners = append(sharedProvisioners, provisioner)
+			}
+			if result.Init != nil {
+				data.Init = result.Init
+			}
+			data.State = resState.State
+			sharedResources[provisioner] = append(sharedResources[provisioner], data)
+		}
+	}
+
+	for _, provisioner := range sharedProvisioners {
+		sharedManifest, err := generateResourceManifest(provisioner.SharedManifestsTemplate, SharedData{
+			Resources: sharedResources[provisioner],
+			Shared:    out.SharedState,
+		})
+		if err != nil {
+			return "", nil, fmt.Errorf("provisioner '%s': failed to generate shared manifests: %w", provisioner.Uri, err)
+		} else if err := declare(sharedManifest, fmt.Sprintf("provisioner '%s' shared manifests", provisioner.Uri)); err != nil {
+			return "", nil, err
+		}
+		if sharedManifest != "" {
+			manifests = manifests + "\n" + sharedManifest
+		}
 	}
 
 	return manifests, out, nil
 }
 
-func renderTemplateAndDecode(raw string, data interface{}, out interface{}) error {
-	raw = strings.TrimSpace(raw)
-	if raw == "" {
+// EffectiveParams returns the schema of the params of the provisioner, including its required params.
+func (p *Provisioner) EffectiveParams() ParamsSchema {
+	out := maps.Clone(p.Params)
+	for _, name := range p.RequiredParams {
+		if out == nil {
+			out = make(ParamsSchema)
+		}
+		schema := out[name]
+		schema.Required = true
+		out[name] = schema
+	}
+	return out
+}
+
+// checkParams returns the params with the defaults of the provisioner filled in. It returns an error if a required
+// param is missing, if a param does not match its schema, or if the provisioner declares its params and one of the
+// given params is not declared.
+func (p *Provisioner) checkParams(params map[string]interface{}) (map[string]interface{}, error) {
+	schemas := p.EffectiveParams()
+	for _, name := range schemas.Names() {
+		if _, ok := params[name]; !ok && schemas[name].Required {
+			return nil, fmt.Errorf("missing required param '%s' for provisioner '%s'", name, p.Uri)
+		}
+	}
+	if len(schemas) == 0 {
+		return params, nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		schema, ok := schemas[name]
+		if !ok {
+			return nil, fmt.Errorf("param '%s' is not declared by provisioner '%s', expected one of: %s", name, p.Uri, strings.Join(schemas.Names(), ", "))
+		} else if err := schema.check(params[name]); err != nil {
+			return nil, fmt.Errorf("param '%s' of provisioner '%s': %w", name, p.Uri, err)
+		}
+	}
+	out := maps.Clone(params)
+	for name, schema := range schemas {
+		if _, ok := out[name]; !ok && schema.Default != nil {
+			if out == nil {
+				out = make(map[string]interface{})
+			}
+			out[name] = schema.Default
+		}
+	}
+	return out, nil
+}
+
+// checkOutputs returns an error if one of the expected outputs of the provisioner is missing.
+func (p *Provisioner) checkOutputs(outputs map[string]interface{}) error {
+	missing := make([]string, 0)
+	for _, name := range p.Outputs {
+		if _, ok := outputs[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("provisioner '%s' did not produce the expected outputs: %s", p.Uri, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// provisionResult is what a provisioner returns for a resource.
+type provisionResult struct {
+	// Init are the working values rendered by the init template of a template provisioner.
+	Init map[string]interface{}
+	// State is the new state of the resource, nil to keep the current state.
+	State map[string]interface{}
+	// SharedState is merged into the shared state, a nil value removes a key.
+	SharedState map[string]interface{}
+	Outputs     map[string]interface{}
+	// Manifests are the Bicep declarations of the resource.
+	Manifests string
+	// OutputLookup resolves the outputs which are not known in advance, the Outputs are used when it is nil.
+	OutputLookup framework.OutputLookupFunc
+	// SecretOutputs replace the secret outputs of the provisioner when they are not nil.
+	SecretOutputs []string
+}
+
+// provisionTemplate evaluates the templates of a template provisioner in order: init, state, shared, outputs, and
+// manifests. Each template has access to the results of the previous ones.
+func provisionTemplate(provisioner Provisioner, data Data) (*provisionResult, error) {
+	result := &provisionResult{}
+	if err := renderTemplateAndDecode("init", provisioner.InitTemplate, &data, &data.Init); err != nil {
+		return nil, fmt.Errorf("init template failed: %w", err)
+	}
+
+	if strings.TrimSpace(provisioner.StateTemplate) != "" {
+		result.State = make(map[string]interface{})
+		if err := renderTemplateAndDecode("state", provisioner.StateTemplate, &data, &result.State); err != nil {
+			return nil, fmt.Errorf("state template failed: %w", err)
+		}
+		data.State = result.State
+	}
+
+	result.SharedState = make(map[string]interface{})
+	if err := renderTemplateAndDecode("shared", provisioner.SharedStateTemplate, &data, &result.SharedState); err != nil {
+		return nil, fmt.Errorf("shared template failed: %w", err)
+	} else if len(result.SharedState) > 0 {
+		data.Shared = patchMap(data.Shared, result.SharedState)
+	}
+
+	result.Outputs = make(map[string]interface{})
+	if err := renderTemplateAndDecode("outputs", provisioner.OutputsTemplate, &data, &result.Outputs); err != nil {
+		return nil, fmt.Errorf("outputs template failed: %w", err)
+	}
+
+	var err error
+	if result.Manifests, err = generateResourceManifest(provisioner.ManifestsTemplate, data); err != nil {
+		return nil, fmt.Errorf("failed to generate resource manifest %s: %w", data.Type, err)
+	}
+	result.Init = data.Init
+	return result, nil
+}
+
+func renderTemplateAndDecode(name string, raw string, data interface{}, out interface{}) error {
+	// only the end is trimmed so that the line numbers of the errors match the template
+	raw = strings.TrimRightFunc(raw, unicode.IsSpace)
+	if strings.TrimSpace(raw) == "" {
 		return nil
 	}
-	prepared, err := template.New("").Funcs(sprig.FuncMap()).Parse(raw)
+	prepared, err := newTemplate(name).Parse(raw)
 	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
+		return fmt.Errorf("failed to parse template: %w", withTemplateLine(err, raw))
 	}
 	buff := new(bytes.Buffer)
 	if err := prepared.Execute(buff, data); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+		return fmt.Errorf("failed to execute template: %w", withTemplateLine(err, raw))
 	}
 	buffContents := buff.String()
 	if strings.TrimSpace(buff.String()) == "" {
@@ -145,7 +532,7 @@ func renderTemplateAndDecode(raw string, data interface{}, out interface{}) erro
 	var intermediate interface{}
 	if err := yaml.Unmarshal([]byte(buffContents), &intermediate); err != nil {
 		slog.Debug(fmt.Sprintf("template output was '%s' from template '%s'", buffContents, raw))
-		return fmt.Errorf("failed to decode output: %w", err)
+		return fmt.Errorf("failed to decode output: %w", withRenderedOutput(err, buffContents))
 	}
 	err = mapstructure.Decode(intermediate, &out)
 	if err != nil {
@@ -155,16 +542,62 @@ func renderTemplateAndDecode(raw string, data interface{}, out interface{}) erro
 	return nil
 }
 
-func generateResourceManifest(resourceTypeTemplate string, data Data) (string, error) {
-	t, err := template.New("").Funcs(sprig.FuncMap()).Parse(resourceTypeTemplate)
+func generateResourceManifest(resourceTypeTemplate string, data interface{}) (string, error) {
+	t, err := newTemplate("manifests").Parse(resourceTypeTemplate)
 	if err != nil {
-		return "", err
+		return "", withTemplateLine(err, resourceTypeTemplate)
 	}
 	var buf bytes.Buffer
 
 	if err := t.Execute(&buf, data); err != nil {
-		return "", err
+		return "", withTemplateLine(err, resourceTypeTemplate)
 	}
 
 	return strings.TrimSpace(buf.String()), nil
 }
+
+// templateLineRegex finds the line in the errors of text/template, e.g. 'template: outputs:3:12: executing ...'.
+var templateLineRegex = regexp.MustCompile(`^template: [^:]+:(\d+)`)
+
+// withTemplateLine appends the line of the template which the error refers to.
+func withTemplateLine(err error, raw string) error {
+	match := templateLineRegex.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+	lineNumber, _ := strconv.Atoi(match[1])
+	lines := strings.Split(raw, "\n")
+	if lineNumber < 1 || lineNumber > len(lines) {
+		return err
+	}
+	return fmt.Errorf("%w\n  template line %d: %s", err, lineNumber, strings.TrimSpace(lines[lineNumber-1]))
+}
+
+// withRenderedOutput appends the numbered lines of the rendered output which failed to decode.
+func withRenderedOutput(err error, output string) error {
+	out := new(strings.Builder)
+	for i, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		_, _ = fmt.Fprintf(out, "\n  %3d | %s", i+1, line)
+	}
+	return fmt.Errorf("%w, rendered output:%s", err, out.String())
+}
+
+// patchMap returns a copy of the current map with the patch merged in recursively, a nil value in the patch removes the
+// key from the current map.
+func patchMap(current map[string]interface{}, patch map[string]interface{}) map[string]interface{} {
+	out := maps.Clone(current)
+	if out == nil {
+		out = make(map[string]interface{}, len(patch))
+	}
+	for key, value := range patch {
+		if value == nil {
+			delete(out, key)
+		} else if patchValue, ok := value.(map[string]interface{}); ok {
+			currentValue, _ := out[key].(map[string]interface{})
+			out[key] = patchMap(currentValue, patchValue)
+		} else {
+			out[key] = value
+		}
+	}
+	return out
+}
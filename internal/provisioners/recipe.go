@@ -0,0 +1,90 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provisioners
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/score-spec/score-radius/internal/bicep"
+)
+
+const recipeScheme = "recipe"
+
+// DefaultRecipeName is the recipe of the portable resources when the provisioner does not name one, like in Radius.
+const DefaultRecipeName = "default"
+
+// DefaultPortableApiVersion is the api version of the portable resource types which don't specify one.
+const DefaultPortableApiVersion = "2023-10-01-preview"
+
+// validateRecipe checks that a recipe:// provisioner declares its portable resource type and no template, since its
+// manifests and outputs are generated.
+func (p *Provisioner) validateRecipe() error {
+	if p.PortableType == "" {
+		return fmt.Errorf("recipe provisioner requires a portable_type, e.g. Applications.Datastores/redisCaches")
+	}
+	if templates := p.Templates(); len(templates) > 0 {
+		return fmt.Errorf("recipe provisioner does not support templates, found '%s'", templates[0][0])
+	}
+	return nil
+}
+
+// portableTypeWithVersion returns the portable resource type with its api version.
+func (p *Provisioner) portableTypeWithVersion() string {
+	if strings.Contains(p.PortableType, "@") {
+		return p.PortableType
+	}
+	return p.PortableType + "@" + DefaultPortableApiVersion
+}
+
+// provisionRecipe declares the portable resource of a recipe:// provisioner, provisioned by the recipe of the
+// environment with the params of the resource as recipe parameters. The expected outputs are read from the properties
+// of the portable resource, and from its listSecrets() function for the secret outputs.
+func provisionRecipe(provisioner Provisioner, data Data) (*provisionResult, error) {
+	recipeName := provisioner.Recipe
+	if recipeName == "" {
+		recipeName = DefaultRecipeName
+	}
+	parameters := maps.Clone(data.Params)
+	for _, name := range ConnectionParams {
+		delete(parameters, name)
+	}
+
+	manifests := new(strings.Builder)
+	_, _ = fmt.Fprintf(manifests, "resource %s '%s' = {\n", data.SymbolicName, provisioner.portableTypeWithVersion())
+	_, _ = fmt.Fprintf(manifests, "  name: %s\n", bicep.LiteralString(data.Name))
+	manifests.WriteString("  properties: {\n    application: application\n    environment: environment\n    recipe: {\n")
+	_, _ = fmt.Fprintf(manifests, "      name: %s\n", bicep.LiteralString(recipeName))
+	if len(parameters) > 0 {
+		value, err := bicep.Value(parameters, 6)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write the recipe parameters of provisioner '%s': %w", provisioner.Uri, err)
+		}
+		_, _ = fmt.Fprintf(manifests, "      parameters: %s\n", value)
+	}
+	manifests.WriteString("    }\n  }\n}")
+
+	outputs := make(map[string]interface{}, len(provisioner.Outputs))
+	for _, name := range provisioner.Outputs {
+		if slices.Contains(provisioner.SecretOutputs, name) {
+			outputs[name] = fmt.Sprintf("${%s.listSecrets().%s}", data.SymbolicName, name)
+		} else {
+			outputs[name] = fmt.Sprintf("${%s.properties.%s}", data.SymbolicName, name)
+		}
+	}
+	return &provisionResult{Outputs: outputs, Manifests: manifests.String()}, nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schema generates the JSON schemas of the score-radius files.
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/score-spec/score-radius/internal/convert"
+	"github.com/score-spec/score-radius/internal/provisioners"
+)
+
+// Draft is the JSON schema dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON schema document.
+type Schema = map[string]interface{}
+
+// Custom is implemented by the types which describe their own schema, e.g. when they accept several yaml forms.
+type Custom interface {
+	JSONSchema() Schema
+}
+
+// provisionerDescriptions describes the fields of the provisioners by yaml name.
+var provisionerDescriptions = map[string]string{
+	"uri":               uriDescription(),
+	"type":              "The type of the resources provisioned.",
+	"class":             "The class of the resources provisioned, or * for any class.",
+	"id":                "Restricts the provisioner to the resource with this id.",
+	"workloads":         "Restricts the provisioner to the resources declared by one of these workloads.",
+	"metadata_selector": "Restricts the provisioner to the resources whose metadata contains these values.",
+	"format":            "The format of the manifests, bicep by default.",
+	"description":       "The description shown by 'provisioners list'.",
+	"init":              "The template of the working values available as .Init to the next templates.",
+	"state":             "The template of the new state of the resource, available as .State.",
+	"shared":            "The template of the values merged into the shared state, available as .Shared.",
+	"manifests":         "The template of the Bicep declarations of the resource.",
+	"shared_manifests":  "The template of the Bicep declarations shared by the resources of the provisioner, rendered once with .Resources.",
+	"params":            "The params accepted by the provisioner, as a list of names or a schema by name.",
+	"required_params":   "The params that the resources must set.",
+	"expected_outputs":  "The outputs that the provisioner must return.",
+	"secret_outputs":    "The outputs holding secrets, never written in plain container definitions.",
+	"no_connection":     "Whether the resource has no Radius resource which the containers can connect to.",
+	"connection_output": "The output holding the source of the connections to the resource, like the url of another container, instead of the id of its Radius resource.",
+	"outputs":           "The template of the outputs of the resource.",
+	"args":              "The arguments of the executable of a cmd:// provisioner.",
+	"portable_type":     "The Radius portable resource type declared by a recipe:// provisioner, e.g. Applications.Datastores/redisCaches.",
+	"recipe":            "The name of the environment recipe of a recipe:// provisioner, 'default' when it is not set.",
+}
+
+// uriDescription describes the uri of the provisioners with each of the supported schemes.
+func uriDescription() string {
+	schemes := make([]string, 0, len(provisioners.Schemes))
+	for _, scheme := range provisioners.Schemes {
+		schemes = append(schemes, fmt.Sprintf("%s:// for %s", scheme[0], scheme[1]))
+	}
+	return "The unique uri of the provisioner, " + strings.Join(schemes, ", ") + "."
+}
+
+// Provisioners returns the schema of the *.provisioners.yaml files, generated from the provisioners.Provisioner struct.
+func Provisioners() Schema {
+	out := FromType(reflect.TypeOf(provisioners.Provisioner{}), provisionerDescriptions)
+	out["required"] = []string{"uri", "type"}
+	return Schema{
+		"$schema":     Draft,
+		"title":       "score-radius provisioners file",
+		"description": "A list of score-radius resource provisioners.",
+		"type":        "array",
+		"items":       out,
+	}
+}
+
+// WorkloadExtensions returns the schema of the score-radius specific fields of the Score workloads. It is meant to be
+// combined with the Score schema.
+func WorkloadExtensions() Schema {
+	annotations := Schema{}
+	for name, description := range convert.Annotations {
+		annotations[name] = Schema{"type": "string", "description": description}
+	}
+	return Schema{
+		"$schema":     Draft,
+		"title":       "score-radius workload extensions",
+		"description": "The annotations of the Score workloads understood by score-radius.",
+		"type":        "object",
+		"properties": Schema{
+			"metadata": Schema{
+				"type": "object",
+				"properties": Schema{
+					"annotations": Schema{
+						"type":       "object",
+						"properties": annotations,
+					},
+				},
+			},
+		},
+	}
+}
+
+// FromType returns the schema of a Go type from its yaml tags, like it is decoded with known fields. The descriptions
+// of the fields of the top level struct are given by yaml name.
+func FromType(t reflect.Type, descriptions map[string]string) Schema {
+	if custom, ok := reflect.New(t).Elem().Interface().(Custom); ok {
+		return custom.JSONSchema()
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return FromType(t.Elem(), descriptions)
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": "array", "items": FromType(t.Elem(), nil)}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": FromType(t.Elem(), nil)}
+	case reflect.Struct:
+		properties := Schema{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			} else if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			property := FromType(field.Type, nil)
+			if description, ok := descriptions[name]; ok {
+				property["description"] = description
+			}
+			properties[name] = property
+		}
+		return Schema{"type": "object", "properties": properties, "additionalProperties": false}
+	}
+	// interfaces accept any value
+	return Schema{}
+}
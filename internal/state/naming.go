@@ -0,0 +1,133 @@
+// Copyright 2025 The Score Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/score-spec/score-go/framework"
+
+	"github.com/score-spec/score-radius/internal/bicep"
+)
+
+// SecretStoreSuffix is appended to the symbolic name of a workload to name its Applications.Core/secretStores.
+const SecretStoreSuffix = "_secrets"
+
+// reservedSymbolicNames are declared by the header of every generated Bicep file.
+var reservedSymbolicNames = []string{"application", "environment"}
+
+// WithSymbolicNames returns a copy of the state where each workload and resource has a Bicep symbolic name. The
+// names already in the state are kept so that they are stable across generations, the new ones are derived from the
+// workload name or the last part of the resource id and made unique within the Bicep file.
+func WithSymbolicNames(currentState *State) *State {
+	out := *currentState
+	out.Workloads = maps.Clone(currentState.Workloads)
+	out.Resources = maps.Clone(currentState.Resources)
+
+	taken := SymbolicNames(&out)
+	for _, name := range reservedSymbolicNames {
+		taken[name] = true
+	}
+
+	for _, workloadName := range slices.Sorted(maps.Keys(out.Workloads)) {
+		workload := out.Workloads[workloadName]
+		if workload.Extras.SymbolicName != "" {
+			continue
+		}
+		workload.Extras.SymbolicName = uniqueSymbolicName(taken, bicep.Identifier(workloadName), SecretStoreSuffix)
+		out.Workloads[workloadName] = workload
+	}
+
+	resUids := slices.SortedFunc(maps.Keys(out.Resources), func(a, b framework.ResourceUid) int {
+		return strings.Compare(string(a), string(b))
+	})
+	for _, resUid := range resUids {
+		res := out.Resources[resUid]
+		if res.Extras.SymbolicName != "" {
+			continue
+		}
+		idParts := strings.Split(res.Id, ".")
+		candidates := []string{bicep.Identifier(idParts[len(idParts)-1])}
+		if len(idParts) > 1 {
+			// a resource private to a workload is next prefixed by its workload name
+			candidates = append(candidates, bicep.Identifier(res.Id))
+		}
+		candidates = append(candidates, bicep.Identifier(res.Type+"_"+res.Id))
+		for _, candidate := range candidates {
+			if !taken[candidate] {
+				res.Extras.SymbolicName = candidate
+				taken[candidate] = true
+				break
+			}
+		}
+		if res.Extras.SymbolicName == "" {
+			res.Extras.SymbolicName = uniqueSymbolicName(taken, candidates[0])
+		}
+		out.Resources[resUid] = res
+	}
+	return &out
+}
+
+// SymbolicNames returns the Bicep symbolic names given to the workloads, their secret stores, and the resources of
+// the state.
+func SymbolicNames(currentState *State) map[string]bool {
+	names := make(map[string]bool)
+	for _, workload := range currentState.Workloads {
+		if workload.Extras.SymbolicName != "" {
+			names[workload.Extras.SymbolicName] = true
+			names[workload.Extras.SymbolicName+SecretStoreSuffix] = true
+		}
+	}
+	for _, res := range currentState.Resources {
+		if res.Extras.SymbolicName != "" {
+			names[res.Extras.SymbolicName] = true
+		}
+	}
+	return names
+}
+
+// RadiusName returns the Radius name of a resource derived from its Bicep symbolic name, so that it is as unique as
+// the symbolic name: the underscores are replaced by dashes, and the leading and trailing ones are trimmed.
+func RadiusName(symbolicName string) string {
+	return strings.Trim(strings.ReplaceAll(symbolicName, "_", "-"), "-")
+}
+
+// uniqueSymbolicName returns the base name, or the base name with the first free numeric suffix, such that the name
+// and its derived names with the given suffixes are not taken. The returned names are then marked as taken.
+func uniqueSymbolicName(taken map[string]bool, base string, suffixes ...string) string {
+	isFree := func(name string) bool {
+		if taken[name] {
+			return false
+		}
+		for _, suffix := range suffixes {
+			if taken[name+suffix] {
+				return false
+			}
+		}
+		return true
+	}
+	name := base
+	for i := 2; !isFree(name); i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	taken[name] = true
+	for _, suffix := range suffixes {
+		taken[name+suffix] = true
+	}
+	return name
+}
@@ -30,9 +30,25 @@ const (
 	FileName                      = "state.yaml"
 )
 
-type WorkloadExtras struct{}
+type WorkloadExtras struct {
+	// SymbolicName is the Bicep symbolic name of the Applications.Core/containers resource of the workload.
+	SymbolicName string `yaml:"symbolic_name,omitempty"`
+}
 
-type ResourceExtras struct{}
+type ResourceExtras struct {
+	// SymbolicName is the Bicep symbolic name that the provisioner must give to the resource.
+	SymbolicName string `yaml:"symbolic_name,omitempty"`
+	// SecretOutputs are the names of the outputs marked as secret by the provisioner of the resource.
+	SecretOutputs []string `yaml:"secret_outputs,omitempty"`
+	// NoConnection indicates that the containers must not connect to the resource since its provisioner declares no
+	// Radius resource.
+	NoConnection bool `yaml:"no_connection,omitempty"`
+	// ConnectionSource is the source of the connections to the resource, like the url of another container, instead
+	// of the id of its Radius resource.
+	ConnectionSource string `yaml:"connection_source,omitempty"`
+	// ProvisionerMatch is the reason why the provisioner of the resource was chosen.
+	ProvisionerMatch string `yaml:"provisioner_match,omitempty"`
+}
 
 type State = framework.State[framework.NoExtras, WorkloadExtras, ResourceExtras]
 